@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// askHeadingWeight scales the bonus for query terms found in a passage's
+	// headings relative to its BM25 score
+	askHeadingWeight = 1.5
+
+	// maxEvidenceChars caps the text quoted from a single passage
+	maxEvidenceChars = 1500
+)
+
+// questionWords are common in questions but carry no meaning for retrieval
+var questionWords = map[string]bool{
+	"about": true, "any": true, "could": true, "get": true, "have": true,
+	"make": true, "me": true, "my": true, "need": true, "should": true,
+	"there": true, "way": true, "we": true, "who": true, "why": true,
+	"would": true,
+}
+
+// evidence is one cited passage of an evidence pack
+type evidence struct {
+	Rank      int     `json:"rank"`
+	Citation  string  `json:"citation"`
+	File      string  `json:"file"`
+	Title     string  `json:"title"`
+	Heading   string  `json:"heading"`
+	StartLine int     `json:"start_line"`
+	EndLine   int     `json:"end_line"`
+	URI       string  `json:"uri"`
+	Score     float64 `json:"score"`
+	Text      string  `json:"text"`
+	Truncated bool    `json:"truncated,omitempty"`
+}
+
+// questionTerms tokenizes a natural-language question into search terms
+func questionTerms(question string) []string {
+	var terms []string
+	for _, term := range uniqueTerms(tokenize(question)) {
+		if !questionWords[term] {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// headingScore rewards query terms that appear in a passage's own heading,
+// and to a lesser degree in its parent headings and document title
+func (idx *searchIndex) headingScore(passage docSection, terms []string) float64 {
+	own := make(map[string]bool)
+	for _, token := range tokenize(passage.Title) {
+		own[token] = true
+	}
+	parents := make(map[string]bool)
+	if len(passage.Path) > 1 {
+		for _, token := range tokenize(strings.Join(passage.Path[:len(passage.Path)-1], " ")) {
+			parents[token] = true
+		}
+	}
+	doc := make(map[string]bool)
+	for _, token := range tokenize(passage.DocTitle) {
+		doc[token] = true
+	}
+
+	score := 0.0
+	for _, term := range terms {
+		switch {
+		case own[term]:
+			score += idx.idf(term)
+		case parents[term]:
+			score += 0.5 * idx.idf(term)
+		case doc[term]:
+			score += 0.3 * idx.idf(term)
+		}
+	}
+	return score
+}
+
+// Ask ranks passages for a question by BM25 plus a heading match bonus and
+// returns them as cited evidence
+func (idx *searchIndex) Ask(set *docSet, question string, limit int) []evidence {
+	terms := questionTerms(question)
+	scores := idx.scores(terms)
+	for i := range scores {
+		scores[i] += askHeadingWeight * idx.headingScore(idx.passages[i], terms)
+	}
+
+	var pack []evidence
+	for n, i := range rank(scores, limit) {
+		passage := idx.passages[i]
+		text, truncated := truncateLines(passage.Text, maxEvidenceChars)
+
+		pack = append(pack, evidence{
+			Rank:      n + 1,
+			Citation:  fmt.Sprintf("%s:%d-%d", passage.File, passage.StartLine, passage.EndLine),
+			File:      passage.File,
+			Title:     passage.DocTitle,
+			Heading:   passage.HeadingPath(),
+			StartLine: passage.StartLine,
+			EndLine:   passage.EndLine,
+			URI:       docSectionURI(set, passage.File, passage.Path),
+			Score:     math.Round(scores[i]*1000) / 1000,
+			Text:      text,
+			Truncated: truncated,
+		})
+	}
+
+	return pack
+}
+
+// truncateLines shortens text to at most max bytes, cutting at a line break
+// when possible
+func truncateLines(text string, max int) (string, bool) {
+	if len(text) <= max {
+		return text, false
+	}
+	cut := strings.ToValidUTF8(text[:max], "")
+	if i := strings.LastIndex(cut, "\n"); i > max/2 {
+		cut = cut[:i]
+	}
+	return cut, true
+}
+
+func handleBaseAsk(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	question, err := request.RequireString("question")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	limit := request.GetInt("limit", 5)
+	switch {
+	case limit < 1:
+		limit = 5
+	case limit > 10:
+		limit = 10
+	}
+
+	set, err := docSetFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	pack := set.Index().Ask(set, question, limit)
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Evidence for: %s\n\n", question)
+	if len(pack) == 0 {
+		text.WriteString("No documentation passages match this question.")
+	}
+	for _, e := range pack {
+		fmt.Fprintf(&text, "[%d] %s — %s\n", e.Rank, e.Citation, e.Heading)
+		text.WriteString(e.Text)
+		if e.Truncated {
+			fmt.Fprintf(&text, "\n[... passage truncated, read %s for the rest]", e.URI)
+		}
+		text.WriteString("\n\n")
+	}
+	if len(pack) > 0 {
+		text.WriteString("Answer only from the passages above and cite them by number and file:lines, e.g. [1] " + pack[0].Citation + ".")
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{
+		"question": question,
+		"version":  set.Version,
+		"evidence": pack,
+	}, strings.TrimSpace(text.String())), nil
+}
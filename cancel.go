@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolCancellations cancels the context of a running tool call when the
+// client sends notifications/cancelled for it, which mcp-go does not do by
+// itself. Tool handlers do not see the JSON-RPC request ID, so a
+// before-call hook tags the request's _meta with it and a tool middleware
+// wraps the handler context in a cancellable one.
+type toolCancellations struct {
+	mu      sync.Mutex
+	pending map[*mcp.Meta]string
+	running map[string]context.CancelFunc
+}
+
+func newToolCancellations() *toolCancellations {
+	return &toolCancellations{
+		pending: make(map[*mcp.Meta]string),
+		running: make(map[string]context.CancelFunc),
+	}
+}
+
+// methodNotificationCancelled is sent by clients to cancel a request
+const methodNotificationCancelled = "notifications/cancelled"
+
+// serverOptions returns the options that install the hook and the
+// middleware on a new server
+func (c *toolCancellations) serverOptions(hooks *server.Hooks) []server.ServerOption {
+	hooks.AddBeforeCallTool(c.beforeCallTool)
+	hooks.AddOnError(c.onError)
+	return []server.ServerOption{
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(c.middleware),
+	}
+}
+
+// register handles notifications/cancelled on the server
+func (c *toolCancellations) register(s *server.MCPServer) {
+	s.AddNotificationHandler(methodNotificationCancelled, c.handleCancelled)
+}
+
+// requestKey identifies a request of the client session in ctx
+func requestKey(ctx context.Context, id any) string {
+	sessionID := ""
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		sessionID = session.SessionID()
+	}
+	return fmt.Sprintf("%s/%v", sessionID, id)
+}
+
+func (c *toolCancellations) beforeCallTool(ctx context.Context, id any, request *mcp.CallToolRequest) {
+	if request.Params.Meta == nil {
+		request.Params.Meta = &mcp.Meta{}
+	}
+
+	c.mu.Lock()
+	c.pending[request.Params.Meta] = requestKey(ctx, id)
+	c.mu.Unlock()
+}
+
+// onError forgets tool calls that failed before reaching the middleware,
+// e.g. because the tool does not exist
+func (c *toolCancellations) onError(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+	if request, ok := message.(*mcp.CallToolRequest); ok {
+		c.mu.Lock()
+		delete(c.pending, request.Params.Meta)
+		c.mu.Unlock()
+	}
+}
+
+func (c *toolCancellations) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		c.mu.Lock()
+		key, ok := c.pending[request.Params.Meta]
+		delete(c.pending, request.Params.Meta)
+		c.mu.Unlock()
+		if !ok {
+			return next(ctx, request)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		c.mu.Lock()
+		c.running[key] = cancel
+		c.mu.Unlock()
+
+		defer func() {
+			c.mu.Lock()
+			delete(c.running, key)
+			c.mu.Unlock()
+			cancel()
+		}()
+
+		return next(ctx, request)
+	}
+}
+
+func (c *toolCancellations) handleCancelled(ctx context.Context, notification mcp.JSONRPCNotification) {
+	id, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	cancel, ok := c.running[requestKey(ctx, id)]
+	c.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
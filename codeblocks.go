@@ -0,0 +1,356 @@
+package main
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// codeBlock is a fenced code block of a markdown file. StartLine is the
+// 1-based file line of the first line of code.
+type codeBlock struct {
+	File      string
+	Path      []string
+	Declared  string
+	Lang      string
+	StartLine int
+	EndLine   int
+	Code      string
+	Context   string
+}
+
+var (
+	envLinePattern   = regexp.MustCompile(`^[A-Z][A-Z0-9_]*=\S*$`)
+	envPrefixPattern = regexp.MustCompile(`^([A-Z][A-Z0-9_]*=\S*\s+)+\S`)
+	yamlKeyPattern   = regexp.MustCompile(`^[A-Za-z_][\w-]*:(\s|$)`)
+	goHintPattern    = regexp.MustCompile(`\bfunc\b|:=|^package \w|^import\b|\btype \w+ (struct|interface)\b|\w\.\w+\(|^//`)
+	jsHintPattern    = regexp.MustCompile(`=>|\bimport .* from |^export |^function |^const \{|\bawait\b`)
+	sqlPattern       = regexp.MustCompile(`^(CREATE|SELECT|INSERT|UPDATE|DELETE|ALTER) `)
+	httpPattern      = regexp.MustCompile(`^(GET|POST|PUT|PATCH|DELETE) /`)
+	jsFilePattern    = regexp.MustCompile(`^//\s*\S+\.(js|jsx|ts|tsx|vue)$`)
+)
+
+// shellCommands start lines of shell snippets in the docs
+var shellCommands = []string{
+	"base ", "go ", "curl ", "cd ", "export ", "npm ", "npx ", "docker", "git ",
+	"make ", "brew ", "chmod ", "sudo ", "mkdir ", "cp ", "mv ", "rm ", "wget ",
+	"ls", "cat ", "echo ", "source ", "$ ", "iwr ", "powershell", "set ",
+}
+
+// codeLanguages maps fence info strings to canonical language names
+var codeLanguages = map[string]string{
+	"sh": "bash", "shell": "bash", "zsh": "bash", "console": "bash",
+	"golang": "go", "js": "javascript", "yml": "yaml", "dotenv": "env",
+}
+
+// scanCodeBlocks returns the fenced code blocks of a markdown file with their
+// heading path and the prose paragraph introducing them
+func scanCodeBlocks(file, content string) []codeBlock {
+	lines := splitLines(content)
+
+	var blocks []codeBlock
+	var stack []markdownHeading
+	var prose []string
+	var current *codeBlock
+	fence := ""
+
+	for i := frontmatterLineCount(lines); i < len(lines); i++ {
+		line := lines[i]
+
+		if marker := fenceMarker(line); marker != "" {
+			if fence == "" {
+				fence = marker
+				info := strings.Fields(strings.TrimLeft(strings.TrimSpace(line), marker[:1]))
+				path := make([]string, len(stack))
+				for j, h := range stack {
+					path[j] = h.Title
+				}
+				current = &codeBlock{File: file, Path: path, StartLine: i + 2, Context: strings.Join(prose, "\n")}
+				if len(info) > 0 {
+					current.Declared = strings.ToLower(info[0])
+				}
+				continue
+			}
+			if isClosingFence(line, fence) {
+				current.EndLine = i
+				if current.EndLine >= current.StartLine {
+					current.Code = strings.Join(lines[current.StartLine-1:current.EndLine], "\n")
+				}
+				current.Lang = codeLanguage(current.Declared, current.Code)
+				blocks = append(blocks, *current)
+				fence, current, prose = "", nil, nil
+				continue
+			}
+		}
+		if fence != "" {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if level, title, ok := parseHeading(line); ok {
+			prose = nil
+			if title == "" {
+				continue
+			}
+			for len(stack) > 0 && stack[len(stack)-1].Level >= level {
+				stack = stack[:len(stack)-1]
+			}
+			stack = append(stack, markdownHeading{Line: i + 1, Level: level, Title: title})
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
+		prose = append(prose, trimmed)
+		if len(prose) > 3 {
+			prose = prose[1:]
+		}
+	}
+
+	return blocks
+}
+
+// codeLanguage returns the canonical language of a block, inferring it from
+// the code when the fence has no info string
+func codeLanguage(declared, code string) string {
+	if declared != "" {
+		if lang, ok := codeLanguages[declared]; ok {
+			return lang
+		}
+		return declared
+	}
+
+	trimmed := strings.TrimSpace(code)
+	if trimmed == "" {
+		return "text"
+	}
+	if strings.Contains(code, "├──") || strings.Contains(code, "└──") {
+		return "text"
+	}
+	if (trimmed[0] == '{' || trimmed[0] == '[') && (json.Valid([]byte(trimmed)) || json.Valid([]byte(strings.SplitN(trimmed, "\n", 2)[0]))) {
+		return "json"
+	}
+
+	firstLine := strings.TrimSpace(strings.SplitN(trimmed, "\n", 2)[0])
+	switch {
+	case strings.HasPrefix(firstLine, "<") || strings.HasPrefix(firstLine, "&lt;"):
+		return "html"
+	case sqlPattern.MatchString(firstLine):
+		return "sql"
+	case httpPattern.MatchString(firstLine):
+		return "http"
+	case jsFilePattern.MatchString(firstLine):
+		return "javascript"
+	}
+
+	var env, shell, yaml, goHints, jsHints, total int
+	continued, startsWithCommand := false, false
+	for _, line := range strings.Split(trimmed, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "`"))
+		if line == "" || (strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "#!")) {
+			continue
+		}
+		total++
+		if total == 1 {
+			startsWithCommand = hasShellCommand(line)
+		}
+		switch {
+		case continued:
+			shell++
+		case envLinePattern.MatchString(line):
+			env++
+		case envPrefixPattern.MatchString(line) || hasShellCommand(line):
+			shell++
+		case yamlKeyPattern.MatchString(line) && !strings.Contains(line, "{"):
+			yaml++
+		}
+		continued = continued || shell > 0
+		continued = continued && strings.HasSuffix(line, "\\")
+		if goHintPattern.MatchString(line) {
+			goHints++
+		}
+		if jsHintPattern.MatchString(line) {
+			jsHints++
+		}
+	}
+
+	switch {
+	case total == 0:
+		return "bash"
+	case env == total:
+		return "env"
+	case env+shell == total || shell*2 > total || startsWithCommand:
+		return "bash"
+	case jsHints > goHints:
+		return "javascript"
+	case goHints > 0:
+		return "go"
+	case yaml*2 > total:
+		return "yaml"
+	}
+	return "text"
+}
+
+func hasShellCommand(line string) bool {
+	for _, command := range shellCommands {
+		if strings.HasPrefix(line, command) {
+			return true
+		}
+	}
+	return false
+}
+
+// goSnippetPackage is the package clause used when wrapping doc snippets
+const goSnippetPackage = "package snippet"
+
+// hasPackageClause reports whether code starts with a package clause after
+// any leading comments, e.g. a "// app/post/model.go" path comment
+func hasPackageClause(code string) bool {
+	inComment := false
+	for _, line := range strings.Split(code, "\n") {
+		line = strings.TrimSpace(line)
+		if inComment {
+			end := strings.Index(line, "*/")
+			if end < 0 {
+				continue
+			}
+			line = strings.TrimSpace(line[end+2:])
+			inComment = false
+		}
+		for strings.HasPrefix(line, "/*") {
+			end := strings.Index(line, "*/")
+			if end < 0 {
+				inComment = true
+				break
+			}
+			line = strings.TrimSpace(line[end+2:])
+		}
+		if inComment || line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		return strings.HasPrefix(line, "package ")
+	}
+	return false
+}
+
+// parseGoSnippet parses a Go snippet from the docs. Snippets are often
+// fragments, so they are tried as a complete file, as top-level declarations
+// and as statements in a function body, with any import lines hoisted. The
+// attempt with the fewest errors wins. Positions in the returned file are on
+// the same lines as in code.
+func parseGoSnippet(fset *token.FileSet, filename, code string) (*ast.File, error) {
+	var imports []string
+	var body []string
+	inImports := false
+	for _, line := range strings.Split(code, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inImports:
+			imports = append(imports, trimmed)
+			body = append(body, "")
+			inImports = trimmed != ")"
+		case strings.HasPrefix(trimmed, "import ("):
+			imports = append(imports, trimmed)
+			body = append(body, "")
+			inImports = true
+		case strings.HasPrefix(trimmed, "import "):
+			imports = append(imports, trimmed)
+			body = append(body, "")
+		default:
+			body = append(body, line)
+		}
+	}
+	header := goSnippetPackage
+	if len(imports) > 0 {
+		header += "; " + strings.ReplaceAll(strings.Join(imports, ";"), "(;", "(")
+	}
+	stripped := strings.Join(body, "\n")
+
+	// A snippet with a package clause is parsed as written; only fragments
+	// are wrapped, as written they fail at once and would beat a wrapping
+	// with a real error
+	attempts := []string{code}
+	if !hasPackageClause(code) {
+		attempts = []string{
+			goSnippetPackage + ";" + code,
+			header + ";" + stripped,
+			header + "; func _() {" + stripped + "\n}",
+			header + ";" + mixedSnippet(body),
+		}
+	}
+
+	var bestFile *ast.File
+	var bestErr error
+	bestCount := -1
+
+	for _, src := range attempts {
+		file, err := parser.ParseFile(fset, filename, src, parser.AllErrors|parser.ParseComments)
+		if file == nil {
+			continue
+		}
+		count := 0
+		if list, ok := err.(scanner.ErrorList); ok {
+			count = len(list)
+		} else if err != nil {
+			count = 1
+		}
+		if bestCount < 0 || count < bestCount {
+			bestFile, bestErr, bestCount = file, err, count
+		}
+		if count == 0 {
+			break
+		}
+	}
+
+	return bestFile, bestErr
+}
+
+// goDeclPattern matches lines starting a top-level declaration
+var goDeclPattern = regexp.MustCompile(`^(func (\w|\()|type \w+ |var \w+ [^=]*$|const \()`)
+
+// mixedSnippet wraps the statements between top-level declarations of a
+// snippet in func _() bodies, opening and closing them on existing lines so
+// that line numbers are kept
+func mixedSnippet(lines []string) string {
+	out := make([]string, len(lines))
+	depth := 0
+	inBody := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		prefix := ""
+		if depth == 0 && trimmed != "" && !strings.HasPrefix(trimmed, "//") {
+			decl := goDeclPattern.MatchString(trimmed)
+			if decl && inBody {
+				prefix, inBody = "};", false
+			} else if !decl && !inBody {
+				prefix, inBody = "func _() {", true
+			}
+		}
+		out[i] = prefix + line
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth < 0 {
+			depth = 0
+		}
+	}
+	if inBody {
+		out = append(out, "}")
+	}
+	return strings.Join(out, "\n")
+}
+
+// CodeBlocks returns the fenced code blocks of every file in the set
+func (s *docSet) CodeBlocks() []codeBlock {
+	var blocks []codeBlock
+	for _, file := range s.Files() {
+		content, err := s.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		blocks = append(blocks, scanCodeBlocks(file, string(content))...)
+	}
+	return blocks
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestParseGoSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		decls   int
+		wantErr bool
+	}{
+		{"file", "package post\n\nfunc A() {}\n", 1, false},
+		{"path comment", "// app/post/module.go\npackage post\n\nimport \"fmt\"\n\nfunc A() { fmt.Println() }\n", 2, false},
+		{"block comment", "/* app/post/module.go\n   generated */\npackage post\n\ntype Post struct{}\n", 1, false},
+		{"declarations", "type Post struct {\n\tTitle string\n}\n\nfunc (p *Post) Name() string { return p.Title }\n", 2, false},
+		{"statements", "router.GET(\"/posts\", list)\nrouter.POST(\"/posts\", create)\n", 1, false},
+		{"declarations with an error", "type Post struct {\n\tTitle string\n}\n\nfunc (p *Post) Name() string { return p.Title\n\nfunc New() *Post { return &Post{} }\n", 2, true},
+		{"statements with an error", "r := router.New()\nr.GET(\"/posts\", list\nr.POST(\"/posts\", create)\n", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parseGoSnippet(token.NewFileSet(), "snippet.go", tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGoSnippet error = %v, want error: %v", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "expected 'package'") {
+				t.Errorf("parseGoSnippet reported the fragment as written: %v", err)
+			}
+			if len(file.Decls) != tt.decls {
+				t.Errorf("got %d declarations, want %d", len(file.Decls), tt.decls)
+			}
+		})
+	}
+}
+
+func TestHasPackageClause(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"package post", true},
+		{"\n// app/post/model.go\n\npackage post", true},
+		{"/* a */ package post", true},
+		{"/*\n package fake\n*/\nfunc A() {}", false},
+		{"func A() {}\n// package post", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := hasPackageClause(tt.code); got != tt.want {
+			t.Errorf("hasPackageClause(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// commandNamePattern matches module and project names passed to the Base
+// CLI, which must not be mistaken for flags
+var commandNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// commandField is one field of a base_generate call, passed to the CLI as
+// name:type or name:type:Model
+type commandField struct {
+	Name  string
+	Type  string
+	Model string
+}
+
+// commandTools runs the Base CLI for the command tools
+type commandTools struct {
+	executor      *ExecutorService
+	confirmations *destroyConfirmations
+}
+
+// registerCommandTools adds the tools that run Base CLI commands
+func registerCommandTools(s *server.MCPServer, executor *ExecutorService) {
+	tools := &commandTools{
+		executor:      executor,
+		confirmations: newDestroyConfirmations(),
+	}
+
+	generateTool := mcp.NewTool("base_generate",
+		mcp.WithDescription("Generate a Base Framework module (model, controller, service, module and validator) with `base g` and register it in app/init.go"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Module name, e.g. post or time_entry"),
+		),
+		mcp.WithArray("fields",
+			mcp.Description("Fields of the model. See base_field_types for the available types"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":  map[string]any{"type": "string", "description": "Field name, e.g. title or author"},
+					"type":  map[string]any{"type": "string", "description": "CLI field type, e.g. string, text, decimal, image or belongsTo"},
+					"model": map[string]any{"type": "string", "description": "Related model for relationship types, e.g. User"},
+				},
+				"required": []string{"name", "type"},
+			}),
+		),
+		withProjectRootArg(),
+		mcp.WithDestructiveHintAnnotation(false),
+	)
+	s.AddTool(generateTool, tools.handleGenerate)
+
+	newTool := mcp.NewTool("base_new",
+		mcp.WithDescription("Create a new Base Framework project with `base new`, downloading the latest project template"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Project name, e.g. my-api"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Existing directory to create the project directory in. Defaults to the client's workspace root or the server's working directory"),
+		),
+		mcp.WithDestructiveHintAnnotation(false),
+	)
+	s.AddTool(newTool, tools.handleNew)
+
+	destroyTool := mcp.NewTool("base_destroy",
+		mcp.WithDescription("Remove a generated Base Framework module with `base destroy`: its app/<name> directory, model file, tests and app/init.go registration. "+
+			"The first call only returns a preview of what would be removed and a confirmation token; show it to the user and repeat the call with the token once they agree. "+
+			"Clients that support elicitation are asked to confirm directly"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Module name, e.g. post"),
+		),
+		mcp.WithString("confirmation_token",
+			mcp.Description("Token from the preview returned by the previous base_destroy call, confirming the removal"),
+		),
+		withProjectRootArg(),
+		mcp.WithDestructiveHintAnnotation(true),
+	)
+	s.AddTool(destroyTool, tools.handleDestroy)
+
+	docsTool := mcp.NewTool("base_generate_docs",
+		mcp.WithDescription("Generate the Swagger 2.0 API documentation of the project with `base docs` from controller annotations"),
+		mcp.WithString("output",
+			mcp.Description("Output directory for the generated files (default docs)"),
+		),
+		mcp.WithBoolean("static",
+			mcp.Description("Generate the static swagger files (default true)"),
+		),
+		withProjectRootArg(),
+		mcp.WithDestructiveHintAnnotation(false),
+	)
+	s.AddTool(docsTool, tools.handleGenerateDocs)
+
+	updateTool := mcp.NewTool("base_update",
+		mcp.WithDescription("Update the core directory of the project to the latest Base Framework core with `base update`, keeping the app code. Undo it with base_undo"),
+		withProjectRootArg(),
+		mcp.WithDestructiveHintAnnotation(true),
+	)
+	s.AddTool(updateTool, tools.handleUpdate)
+}
+
+func (t *commandTools) handleGenerate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := requireCommandName(request, "name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	fields, err := parseCommandFields(request.GetArguments()["fields"])
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	specs, err := fieldSpecs(fields)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	root, err := projectRootFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	command := append([]string{"base", "generate", name}, specs...)
+	output, err := t.executor.ExecuteGenerate(withCommandProgress(ctx, request, command), root, name, specs)
+	return commandResult(root, command, output, err), nil
+}
+
+func (t *commandTools) handleNew(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := requireCommandName(request, "name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	dir, err := workingDirFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("%s already exists", filepath.Join(dir, name))), nil
+	}
+
+	command := []string{"base", "new", name}
+	output, err := t.executor.ExecuteNew(withCommandProgress(ctx, request, command), dir, name)
+	return commandResult(dir, command, output, err), nil
+}
+
+func (t *commandTools) handleDestroy(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := requireCommandName(request, "name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	root, err := projectRootFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	preview, err := previewDestroy(root, name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error inspecting module %s: %v", name, err)), nil
+	}
+	if preview.Empty() {
+		return mcp.NewToolResultError(fmt.Sprintf("no module %s found in %s: nothing to destroy", name, root)), nil
+	}
+
+	if token := request.GetString("confirmation_token", ""); token != "" {
+		if err := t.confirmations.Redeem(token, preview); err != nil {
+			return t.destroyPreviewResult(preview, err.Error()), nil
+		}
+	} else if confirmed, asked := confirmDestroy(ctx, preview); !asked {
+		return t.destroyPreviewResult(preview, ""), nil
+	} else if !confirmed {
+		return mcp.NewToolResultStructured(map[string]any{
+			"command":   "base destroy " + name,
+			"directory": root,
+			"status":    "declined",
+		}, fmt.Sprintf("base destroy %s was not confirmed. Nothing was removed.", name)), nil
+	}
+
+	command := []string{"base", "destroy", name}
+	output, err := t.executor.ExecuteDestroy(withCommandProgress(ctx, request, command), root, name)
+	return commandResult(root, command, output, err), nil
+}
+
+func (t *commandTools) handleGenerateDocs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	output := strings.TrimSpace(request.GetString("output", ""))
+	if strings.HasPrefix(output, "-") {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid output directory %q", output)), nil
+	}
+	static := request.GetBool("static", true)
+
+	command := []string{"base", "docs"}
+	if output != "" {
+		command = append(command, "--output", output)
+	}
+	if !static {
+		command = append(command, "--no-static")
+	}
+
+	root, err := projectRootFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, err := t.executor.ExecuteDocs(withCommandProgress(ctx, request, command), root, output, static)
+	return commandResult(root, command, result, err), nil
+}
+
+func (t *commandTools) handleUpdate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	root, err := projectRootFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	command := []string{"base", "update"}
+	output, err := t.executor.ExecuteUpdate(withCommandProgress(ctx, request, command), root)
+	return commandResult(root, command, output, err), nil
+}
+
+// requireCommandName reads a required module or project name argument
+func requireCommandName(request mcp.CallToolRequest, key string) (string, error) {
+	name, err := request.RequireString(key)
+	if err != nil {
+		return "", err
+	}
+	name = strings.TrimSpace(name)
+	if !commandNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid %s %q: use letters, digits, '_' and '-', starting with a letter", key, name)
+	}
+	return name, nil
+}
+
+// parseCommandFields reads the fields argument of base_generate. Besides
+// objects it accepts "name:type[:Model]" strings as written for the CLI.
+func parseCommandFields(raw any) ([]commandField, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("fields must be an array")
+	}
+
+	fields := make([]commandField, 0, len(items))
+	for i, item := range items {
+		var field commandField
+		switch v := item.(type) {
+		case string:
+			parts := strings.Split(v, ":")
+			if len(parts) < 2 || len(parts) > 3 {
+				return nil, fmt.Errorf("field %d: %q is not in name:type or name:type:Model form", i+1, v)
+			}
+			field.Name, field.Type = parts[0], parts[1]
+			if len(parts) == 3 {
+				field.Model = parts[2]
+			}
+		case map[string]any:
+			field.Name, _ = v["name"].(string)
+			field.Type, _ = v["type"].(string)
+			field.Model, _ = v["model"].(string)
+		default:
+			return nil, fmt.Errorf("field %d must be an object with name and type", i+1)
+		}
+
+		field.Name = strings.TrimSpace(field.Name)
+		field.Type = strings.TrimSpace(field.Type)
+		field.Model = strings.TrimSpace(field.Model)
+		if field.Name == "" || field.Type == "" {
+			return nil, fmt.Errorf("field %d needs a name and a type", i+1)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// fieldSpecs checks fields against the field type catalog of the default
+// doc set and returns their CLI specs
+func fieldSpecs(fields []commandField) ([]string, error) {
+	types, err := parseFieldTypes(defaultDocSet())
+	if err != nil {
+		return nil, fmt.Errorf("error reading field types: %w", err)
+	}
+
+	known := make(map[string]fieldType, len(types))
+	var names []string
+	for _, ft := range types {
+		if ft.Type == "" {
+			continue
+		}
+		if _, ok := known[strings.ToLower(ft.Type)]; !ok {
+			names = append(names, ft.Type)
+		}
+		known[strings.ToLower(ft.Type)] = ft
+	}
+
+	specs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !commandNamePattern.MatchString(field.Name) {
+			return nil, fmt.Errorf("invalid field name %q", field.Name)
+		}
+
+		ft, ok := known[strings.ToLower(field.Type)]
+		if !ok {
+			return nil, fmt.Errorf("unknown field type %q for %s. Available types: %s", field.Type, field.Name, strings.Join(names, ", "))
+		}
+
+		relationship := strings.Count(ft.Syntax, ":") == 2
+		switch {
+		case relationship && field.Model == "":
+			return nil, fmt.Errorf("field %s: %s needs a related model, e.g. %s", field.Name, ft.Type, ft.Example)
+		case relationship && !commandNamePattern.MatchString(field.Model):
+			return nil, fmt.Errorf("field %s: invalid model %q", field.Name, field.Model)
+		case relationship:
+			specs = append(specs, field.Name+":"+ft.Type+":"+field.Model)
+		case field.Model != "":
+			return nil, fmt.Errorf("field %s: %s does not take a model", field.Name, ft.Type)
+		default:
+			specs = append(specs, field.Name+":"+ft.Type)
+		}
+	}
+	return specs, nil
+}
+
+// commandResult reports the output of a Base CLI command run in dir. A
+// command that failed, was cancelled or timed out is an error result with
+// its status and the output it produced until then.
+func commandResult(dir string, command []string, output string, err error) *mcp.CallToolResult {
+	result := map[string]any{
+		"command":   strings.Join(command, " "),
+		"directory": dir,
+		"status":    "ok",
+		"output":    output,
+	}
+
+	if err != nil {
+		var commandErr *CommandError
+		if !errors.As(err, &commandErr) {
+			return mcp.NewToolResultError(err.Error())
+		}
+		result["status"] = commandErr.Status
+		result["error"] = commandErr.Error()
+
+		text := commandErr.Error()
+		if strings.TrimSpace(output) != "" {
+			label := "Output"
+			if commandErr.Status != CommandFailed {
+				label = "Output so far"
+			}
+			text += fmt.Sprintf("\n%s:\n%s", label, output)
+		}
+
+		toolResult := mcp.NewToolResultStructured(result, text)
+		toolResult.IsError = true
+		return toolResult
+	}
+
+	text := output
+	if strings.TrimSpace(text) == "" {
+		text = fmt.Sprintf("%s completed without output", strings.Join(command, " "))
+	}
+
+	return mcp.NewToolResultStructured(result, text)
+}
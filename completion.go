@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxCompletionValues is the most values a completion/complete response may carry
+const maxCompletionValues = 100
+
+// relationshipTypes are completed for relationship arguments when the field
+// type catalog cannot be read
+var relationshipTypes = []string{"belongsTo", "hasOne", "hasMany", "manyToMany"}
+
+// docCompletionProvider completes the arguments of the workflow prompts and
+// of the documentation resource template
+type docCompletionProvider struct{}
+
+func (docCompletionProvider) CompletePromptArgument(ctx context.Context, promptName string, argument mcp.CompleteArgument, completionCtx mcp.CompleteContext) (*mcp.Completion, error) {
+	set := completionDocSet(completionCtx.Arguments)
+
+	var candidates []string
+	switch argument.Name {
+	case "version":
+		candidates = docVersions()
+	case "locale":
+		candidates = set.Locales()
+	case "module":
+		candidates = projectModules()
+	case "related", "resource":
+		candidates = projectModels()
+	case "type":
+		if promptName == "add_relationship" {
+			candidates = relationshipFieldTypes(set)
+		}
+	case "fields":
+		return completeFieldSpecs(set, argument.Value), nil
+	}
+
+	return completionValues(candidates, argument.Value), nil
+}
+
+func (docCompletionProvider) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, completionCtx mcp.CompleteContext) (*mcp.Completion, error) {
+	if uri != docsResourceTemplate {
+		return completionValues(nil, ""), nil
+	}
+	set := completionDocSet(completionCtx.Arguments)
+
+	var candidates []string
+	switch argument.Name {
+	case "version":
+		candidates = docVersions()
+	case "locale":
+		candidates = set.Locales()
+	case "name":
+		for _, file := range set.Files() {
+			if name, ok := strings.CutPrefix(file, "docs/"); ok {
+				candidates = append(candidates, strings.TrimSuffix(name, ".md"))
+			}
+		}
+	case "section":
+		if outline, err := set.Outline("docs/" + completionCtx.Arguments["name"]); err == nil {
+			candidates = headingPaths(outline.Headings, nil)
+		}
+	}
+
+	return completionValues(candidates, argument.Value), nil
+}
+
+// completionDocSet returns the doc set selected by the version and locale
+// arguments resolved so far, or the default set
+func completionDocSet(arguments map[string]string) *docSet {
+	set, err := getDocSet(arguments["version"])
+	if err != nil {
+		set = defaultDocSet()
+	}
+	return set.Localized(arguments["locale"])
+}
+
+// completionValues returns the candidates matching value: those starting with
+// it first, then those containing it, ignoring case
+func completionValues(candidates []string, value string) *mcp.Completion {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	var prefixed, contained []string
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+
+		lower := strings.ToLower(candidate)
+		switch {
+		case strings.HasPrefix(lower, value):
+			prefixed = append(prefixed, candidate)
+		case strings.Contains(lower, value):
+			contained = append(contained, candidate)
+		}
+	}
+
+	values := append(prefixed, contained...)
+	completion := &mcp.Completion{Values: values, Total: len(values)}
+	if len(values) > maxCompletionValues {
+		completion.Values = values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+	if completion.Values == nil {
+		completion.Values = []string{}
+	}
+	return completion
+}
+
+// headingPaths lists the heading path of every heading in the tree, e.g.
+// "Authorization System > CanAccess()"
+func headingPaths(headings []*docHeading, parent []string) []string {
+	var paths []string
+	for _, heading := range headings {
+		path := append(append([]string{}, parent...), heading.Title)
+		paths = append(paths, strings.Join(path, " > "))
+		paths = append(paths, headingPaths(heading.Children, path)...)
+	}
+	return paths
+}
+
+// relationshipFieldTypes returns the relationship types of the field type
+// catalog
+func relationshipFieldTypes(set *docSet) []string {
+	types, err := parseFieldTypes(set)
+	if err != nil {
+		return relationshipTypes
+	}
+
+	var names []string
+	for _, ft := range types {
+		if strings.Contains(strings.ToLower(ft.Category), "relationship") && ft.Type != "" {
+			names = append(names, ft.Type)
+		}
+	}
+	if len(names) == 0 {
+		return relationshipTypes
+	}
+	return names
+}
+
+// completeFieldSpecs completes the type of the last field:type spec in a
+// space separated field list, e.g. "title:string body:te" to
+// "title:string body:text"
+func completeFieldSpecs(set *docSet, value string) *mcp.Completion {
+	head := ""
+	last := value
+	if i := strings.LastIndexAny(value, " ,"); i >= 0 {
+		head, last = value[:i+1], value[i+1:]
+	}
+
+	name, typed, ok := strings.Cut(last, ":")
+	if !ok || name == "" {
+		return completionValues(nil, "")
+	}
+
+	types, err := parseFieldTypes(set)
+	if err != nil {
+		return completionValues(nil, "")
+	}
+
+	var candidates []string
+	for _, ft := range types {
+		if ft.Type != "" && strings.HasPrefix(strings.ToLower(ft.Type), strings.ToLower(typed)) {
+			candidates = append(candidates, head+name+":"+ft.Type)
+		}
+	}
+	return completionValues(candidates, "")
+}
+
+// projectRoot returns the root of the Base project around the working
+// directory, or "" outside one
+func projectRoot() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	root, _ := findBaseProject(cwd)
+	return root
+}
+
+// projectModules lists the modules of the Base project in the working
+// directory: the directories under app/ other than models
+func projectModules() []string {
+	root := projectRoot()
+	if root == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "app"))
+	if err != nil {
+		return nil
+	}
+
+	var modules []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != "models" && !strings.HasPrefix(entry.Name(), ".") {
+			modules = append(modules, entry.Name())
+		}
+	}
+	return modules
+}
+
+// projectModels lists the model names of the Base project in the working
+// directory from the files in app/models, e.g. blog_post.go to BlogPost
+func projectModels() []string {
+	root := projectRoot()
+	if root == "" {
+		return nil
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(root, "app", "models", "*.go"))
+
+	var models []string
+	for _, match := range matches {
+		name := strings.TrimSuffix(filepath.Base(match), ".go")
+		if strings.HasSuffix(name, "_test") {
+			continue
+		}
+		models = append(models, pascalCase(name))
+	}
+	sort.Strings(models)
+	return models
+}
+
+// pascalCase turns a snake_case or kebab-case name into PascalCase
+func pascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// destroyConfirmationTTL is how long a base_destroy confirmation token
+// stays valid
+const destroyConfirmationTTL = 2 * time.Minute
+
+// destroyRegistration is a line of app/init.go that base destroy removes
+type destroyRegistration struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// destroyPreview lists what base destroy would remove for a module: the
+// files of app/<name>/, app/models/<name>.go and test/app_test/<name>_test/
+// and the module's import and registration in app/init.go
+type destroyPreview struct {
+	Root          string
+	Module        string
+	Files         []string
+	Registrations []destroyRegistration
+}
+
+// previewDestroy inspects the project at root for the files and
+// registrations of module name
+func previewDestroy(root, name string) (*destroyPreview, error) {
+	preview := &destroyPreview{Root: root, Module: name}
+
+	for _, dir := range []string{
+		filepath.Join("app", name),
+		filepath.Join("test", "app_test", name+"_test"),
+	} {
+		err := filepath.WalkDir(filepath.Join(root, dir), func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !entry.IsDir() {
+				rel, _ := filepath.Rel(root, path)
+				preview.Files = append(preview.Files, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	model := filepath.Join("app", "models", name+".go")
+	if _, err := os.Stat(filepath.Join(root, model)); err == nil {
+		preview.Files = append(preview.Files, filepath.ToSlash(model))
+	}
+	sort.Strings(preview.Files)
+
+	registrations, err := initRegistrations(root, name)
+	if err != nil {
+		return nil, err
+	}
+	preview.Registrations = registrations
+
+	return preview, nil
+}
+
+// initRegistrations finds the import of the module package and the
+// modules["<name>"] entry in app/init.go
+func initRegistrations(root, name string) ([]destroyRegistration, error) {
+	file, err := os.Open(filepath.Join(root, "app", "init.go"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	importSuffix := "/app/" + name + `"`
+	entry := `modules["` + name + `"]`
+
+	var registrations []destroyRegistration
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if strings.HasSuffix(text, importSuffix) || strings.Contains(text, entry) {
+			registrations = append(registrations, destroyRegistration{
+				File: "app/init.go",
+				Line: line,
+				Text: text,
+			})
+		}
+	}
+	return registrations, scanner.Err()
+}
+
+// Empty reports whether there is nothing to destroy
+func (p *destroyPreview) Empty() bool {
+	return len(p.Files) == 0 && len(p.Registrations) == 0
+}
+
+// fingerprint identifies the previewed state, so that a confirmation is
+// not used for files added after the preview
+func (p *destroyPreview) fingerprint() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", p.Root, p.Module)
+	for _, file := range p.Files {
+		fmt.Fprintf(h, "%s\x00", file)
+	}
+	for _, registration := range p.Registrations {
+		fmt.Fprintf(h, "%d:%s\x00", registration.Line, registration.Text)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// String lists the files and registrations for the user
+func (p *destroyPreview) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "base destroy %s in %s would remove:\n", p.Module, p.Root)
+	for _, file := range p.Files {
+		fmt.Fprintf(&b, "- %s\n", file)
+	}
+	for _, registration := range p.Registrations {
+		fmt.Fprintf(&b, "- %s:%d: %s\n", registration.File, registration.Line, registration.Text)
+	}
+	return b.String()
+}
+
+// destroyConfirmation is an issued confirmation token
+type destroyConfirmation struct {
+	fingerprint string
+	expires     time.Time
+}
+
+// destroyConfirmations issues the single-use tokens that confirm a
+// previewed base_destroy call
+type destroyConfirmations struct {
+	mu     sync.Mutex
+	tokens map[string]destroyConfirmation
+}
+
+func newDestroyConfirmations() *destroyConfirmations {
+	return &destroyConfirmations{tokens: make(map[string]destroyConfirmation)}
+}
+
+// Issue returns a new token confirming preview and when it expires
+func (c *destroyConfirmations) Issue(preview *destroyPreview) (string, time.Time, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(raw)
+	expires := time.Now().Add(destroyConfirmationTTL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for t, confirmation := range c.tokens {
+		if time.Now().After(confirmation.expires) {
+			delete(c.tokens, t)
+		}
+	}
+	c.tokens[token] = destroyConfirmation{fingerprint: preview.fingerprint(), expires: expires}
+	return token, expires, nil
+}
+
+// Redeem uses up token, checking that it confirms preview
+func (c *destroyConfirmations) Redeem(token string, preview *destroyPreview) error {
+	c.mu.Lock()
+	confirmation, ok := c.tokens[token]
+	delete(c.tokens, token)
+	c.mu.Unlock()
+
+	switch {
+	case !ok:
+		return fmt.Errorf("unknown or already used confirmation token")
+	case time.Now().After(confirmation.expires):
+		return fmt.Errorf("the confirmation token expired")
+	case confirmation.fingerprint != preview.fingerprint():
+		return fmt.Errorf("the confirmation token was issued for base destroy %s with different files, or for another module", preview.Module)
+	}
+	return nil
+}
+
+// confirmDestroy asks the user to confirm preview through MCP elicitation.
+// asked is false when the client does not support elicitation or the
+// request failed.
+func confirmDestroy(ctx context.Context, preview *destroyPreview) (confirmed, asked bool) {
+	srv := server.ServerFromContext(ctx)
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if srv == nil || !ok || session.GetClientCapabilities().Elicitation == nil {
+		return false, false
+	}
+
+	result, err := srv.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message: preview.String() + "\nThis cannot be undone.",
+			RequestedSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"confirm": map[string]any{
+						"type":        "boolean",
+						"title":       "Destroy " + preview.Module,
+						"description": "Remove the module's files and registration",
+					},
+				},
+				"required": []string{"confirm"},
+			},
+		},
+	})
+	if err != nil {
+		return false, false
+	}
+
+	if result.Action != mcp.ElicitationResponseActionAccept {
+		return false, true
+	}
+	content, _ := result.Content.(map[string]any)
+	confirmed, _ = content["confirm"].(bool)
+	return confirmed, true
+}
+
+// destroyPreviewResult returns preview with a confirmation token for the
+// client to repeat the call with. reason explains why an earlier token was
+// not accepted.
+func (t *commandTools) destroyPreviewResult(preview *destroyPreview, reason string) *mcp.CallToolResult {
+	token, expires, err := t.confirmations.Issue(preview)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error issuing confirmation token: %v", err))
+	}
+
+	files := preview.Files
+	if files == nil {
+		files = []string{}
+	}
+	registrations := preview.Registrations
+	if registrations == nil {
+		registrations = []destroyRegistration{}
+	}
+
+	result := map[string]any{
+		"command":            "base destroy " + preview.Module,
+		"directory":          preview.Root,
+		"status":             "confirmation required",
+		"files":              files,
+		"registrations":      registrations,
+		"confirmation_token": token,
+		"expires_at":         expires.Format(time.RFC3339),
+	}
+
+	text := preview.String()
+	if reason != "" {
+		result["reason"] = reason
+		text = strings.ToUpper(reason[:1]) + reason[1:] + ". " + text
+	}
+	text += fmt.Sprintf("\nNothing was removed. Show this to the user and, once they agree, call base_destroy again with confirmation_token %q before %s.", token, expires.Format(time.TimeOnly))
+
+	return mcp.NewToolResultStructured(result, text)
+}
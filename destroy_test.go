@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fakeDestroyCLI is a base CLI that, like the real one, asks before
+// destroying and aborts unless the answer is yes
+const fakeDestroyCLI = `#!/bin/sh
+[ "$1" = destroy ] || exit 2
+printf "Are you sure you want to destroy 1 module(s)? [Y/n] "
+read answer || { echo "aborted"; exit 1; }
+case "$answer" in
+y|Y|"") ;;
+*) echo "aborted"; exit 1;;
+esac
+rm -rf "app/$2" "app/models/$2.go" "test/app_test/$2_test"
+grep -v "\"$2\"" app/init.go > app/init.go.tmp && mv app/init.go.tmp app/init.go
+echo "Successfully destroyed module '$2'"
+`
+
+const testInitGo = `package app
+
+import (
+	"myapp/app/post"
+	"myapp/app/postal"
+)
+
+func (am *AppModules) GetAppModules(deps module.Dependencies) map[string]module.Module {
+	modules := make(map[string]module.Module)
+	modules["post"] = post.Init(deps)
+	modules["postal"] = postal.Init(deps)
+	return modules
+}
+`
+
+// writeTestProject creates a Base project with the post and postal modules
+func writeTestProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                               "module myapp\n\nrequire github.com/base-go/base-core v2.1.7\n",
+		"app/init.go":                          testInitGo,
+		"app/post/module.go":                   "package post\n",
+		"app/post/controller.go":               "package post\n",
+		"app/postal/module.go":                 "package postal\n",
+		"app/models/post.go":                   "package models\n",
+		"test/app_test/post_test/post_test.go": "package post_test\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestPreviewDestroy(t *testing.T) {
+	root := writeTestProject(t)
+
+	preview, err := previewDestroy(root, "post")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFiles := []string{"app/models/post.go", "app/post/controller.go", "app/post/module.go", "test/app_test/post_test/post_test.go"}
+	if strings.Join(preview.Files, ",") != strings.Join(wantFiles, ",") {
+		t.Errorf("files = %v, want %v", preview.Files, wantFiles)
+	}
+	if len(preview.Registrations) != 2 || preview.Registrations[0].Line != 4 || preview.Registrations[1].Line != 10 {
+		t.Errorf("registrations = %+v, want the post import and entry on lines 4 and 10", preview.Registrations)
+	}
+
+	empty, err := previewDestroy(root, "comment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !empty.Empty() {
+		t.Errorf("preview of a missing module is not empty: %+v", empty)
+	}
+}
+
+func TestDestroyConfirmations(t *testing.T) {
+	root := writeTestProject(t)
+	post, _ := previewDestroy(root, "post")
+	postal, _ := previewDestroy(root, "postal")
+
+	tests := []struct {
+		name    string
+		redeem  func(c *destroyConfirmations, token string) error
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			redeem: func(c *destroyConfirmations, token string) error { return c.Redeem(token, post) },
+		},
+		{
+			name: "single use",
+			redeem: func(c *destroyConfirmations, token string) error {
+				c.Redeem(token, post)
+				return c.Redeem(token, post)
+			},
+			wantErr: "unknown or already used",
+		},
+		{
+			name:    "unknown",
+			redeem:  func(c *destroyConfirmations, token string) error { return c.Redeem("nope", post) },
+			wantErr: "unknown or already used",
+		},
+		{
+			name: "expired",
+			redeem: func(c *destroyConfirmations, token string) error {
+				confirmation := c.tokens[token]
+				confirmation.expires = time.Now().Add(-time.Second)
+				c.tokens[token] = confirmation
+				return c.Redeem(token, post)
+			},
+			wantErr: "expired",
+		},
+		{
+			name:    "other module",
+			redeem:  func(c *destroyConfirmations, token string) error { return c.Redeem(token, postal) },
+			wantErr: "different files",
+		},
+		{
+			name: "files changed",
+			redeem: func(c *destroyConfirmations, token string) error {
+				changed := *post
+				changed.Files = append([]string{"app/post/service.go"}, post.Files...)
+				return c.Redeem(token, &changed)
+			},
+			wantErr: "different files",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newDestroyConfirmations()
+			token, expires, err := c.Issue(post)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if time.Until(expires) > destroyConfirmationTTL {
+				t.Errorf("token expires in %s, after the TTL", time.Until(expires))
+			}
+
+			err = tt.redeem(c, token)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Redeem: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Redeem error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfirmedDestroy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake CLI is a shell script")
+	}
+
+	root := writeTestProject(t)
+	cli := filepath.Join(t.TempDir(), "base")
+	if err := os.WriteFile(cli, []byte(fakeDestroyCLI), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tools := &commandTools{
+		executor:      &ExecutorService{basePath: cli, builds: make(map[string]string)},
+		confirmations: newDestroyConfirmations(),
+	}
+
+	call := func(arguments map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		var request mcp.CallToolRequest
+		request.Params.Name = "base_destroy"
+		request.Params.Arguments = arguments
+		result, err := tools.handleDestroy(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	preview := call(map[string]any{"name": "post", "project_root": root})
+	if preview.IsError {
+		t.Fatalf("preview failed: %v", preview.Content)
+	}
+	if _, err := os.Stat(filepath.Join(root, "app", "post")); err != nil {
+		t.Fatalf("the preview removed app/post: %v", err)
+	}
+
+	structured := preview.StructuredContent.(map[string]any)
+	token := structured["confirmation_token"].(string)
+	result := call(map[string]any{"name": "post", "project_root": root, "confirmation_token": token})
+	if result.IsError {
+		t.Fatalf("confirmed destroy failed: %v", result.Content)
+	}
+
+	for _, path := range []string{"app/post", "app/models/post.go", "test/app_test/post_test"} {
+		if _, err := os.Stat(filepath.Join(root, path)); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed", path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "app", "postal", "module.go")); err != nil {
+		t.Errorf("app/postal was removed: %v", err)
+	}
+	initGo, _ := os.ReadFile(filepath.Join(root, "app", "init.go"))
+	if strings.Contains(string(initGo), `"post"`) || !strings.Contains(string(initGo), `"postal"`) {
+		t.Errorf("app/init.go after destroy:\n%s", initGo)
+	}
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// base_docs paging limits. Token counts are estimated at four characters per token.
+const (
+	defaultDocsMaxTokens = 20000
+	minDocsMaxTokens     = 500
+	charsPerToken        = 4
+)
+
+// docsCursor marks where the next base_docs page starts
+type docsCursor struct {
+	File   int `json:"f"`
+	Offset int `json:"o"`
+}
+
+func handleBaseDocFile(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	filename, err := request.RequireString("filename")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	set, err := docSetFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	file, ok := set.Resolve(filename)
+	if !ok {
+		available := set.Files()
+		result := mcp.NewToolResultStructured(map[string]any{
+			"error":     "not_found",
+			"filename":  filename,
+			"available": available,
+		}, fmt.Sprintf("Documentation file %q not found. Available files: %s", filename, strings.Join(available, ", ")))
+		result.IsError = true
+		return result, nil
+	}
+
+	doc, err := set.Document(file)
+	if err != nil {
+		return mcp.NewToolResultError("Error reading documentation: " + err.Error()), nil
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{
+		"file":     doc.File,
+		"version":  set.Version,
+		"locale":   set.FileLocale(file),
+		"title":    doc.DisplayTitle(),
+		"metadata": doc.Meta,
+		"content":  doc.Body,
+	}, doc.Body), nil
+}
+
+// docHref returns the web path of a documentation file, e.g. /docs/cli
+func docHref(set *docSet, file string) string {
+	name := strings.TrimSuffix(file, ".md")
+	if short := strings.TrimPrefix(name, "docs/"); short != name {
+		if resolved, ok := set.Resolve(short); ok && resolved == file {
+			name = short
+		}
+	}
+
+	query := url.Values{}
+	if set.Version != defaultDocSet().Version {
+		query.Set("version", set.Version)
+	}
+	if set.Locale != defaultLocale {
+		query.Set("locale", set.Locale)
+	}
+
+	href := "/docs/" + name
+	if len(query) > 0 {
+		href += "?" + query.Encode()
+	}
+	return href
+}
+
+// serveDocs serves the documentation index at /docs/ and raw markdown at
+// /docs/{name}. A version query parameter selects the doc set, a locale
+// query parameter or else the Accept-Language header its translation.
+func serveDocs(w http.ResponseWriter, r *http.Request) {
+	set, err := getDocSet(r.URL.Query().Get("version"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if locale := r.URL.Query().Get("locale"); locale != "" {
+		set = set.Localized(locale)
+	} else {
+		set = negotiateLocale(set, r.Header.Get("Accept-Language"))
+		w.Header().Add("Vary", "Accept-Language")
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/docs/")
+	if name == "" {
+		serveDocsIndex(w, set)
+		return
+	}
+
+	file, ok := set.Resolve(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	doc, err := set.Document(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Header().Set("Content-Language", set.FileLocale(file))
+	fmt.Fprint(w, doc.Body)
+}
+
+func serveDocsIndex(w http.ResponseWriter, set *docSet) {
+	var list strings.Builder
+	for _, file := range set.Files() {
+		doc, err := set.Document(file)
+		if err != nil {
+			continue
+		}
+
+		list.WriteString("        <li>\n")
+		list.WriteString(fmt.Sprintf("            <a href=\"%s\">%s</a>\n", html.EscapeString(docHref(set, file)), html.EscapeString(doc.DisplayTitle())))
+		if description := doc.DisplayDescription(); description != "" {
+			list.WriteString(fmt.Sprintf("            <div class=\"description\">%s</div>\n", html.EscapeString(description)))
+		}
+		list.WriteString("        </li>\n")
+	}
+
+	page := strings.Replace(docsIndexHTML, "{{DOCS_LIST}}", strings.TrimRight(list.String(), "\n"), 1)
+	page = strings.Replace(page, "{{LANG}}", html.EscapeString(set.Locale), 1)
+
+	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Content-Language", set.Locale)
+	fmt.Fprint(w, page)
+}
+
+// docTopicFiles resolves base_docs topics to files in the set. With no
+// topics it returns every file, overview pages first.
+func docTopicFiles(set *docSet, topics []string) ([]string, error) {
+	if len(topics) == 0 {
+		var files []string
+		overview := map[string]bool{}
+		for _, file := range []string{"index.md", "docs/index.md"} {
+			if _, ok := set.Resolve(file); ok {
+				files = append(files, file)
+				overview[file] = true
+			}
+		}
+		for _, file := range set.Files() {
+			if !overview[file] {
+				files = append(files, file)
+			}
+		}
+		return files, nil
+	}
+
+	var files []string
+	var unknown []string
+	for _, topic := range topics {
+		if file, ok := set.Resolve(topic); ok {
+			files = append(files, file)
+		} else {
+			unknown = append(unknown, topic)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown topics: %s. Available files: %s", strings.Join(unknown, ", "), strings.Join(set.Files(), ", "))
+	}
+	return files, nil
+}
+
+func encodeDocsCursor(c docsCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeDocsCursor(s string) (docsCursor, error) {
+	var c docsCursor
+	if s == "" {
+		return c, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil || c.File < 0 || c.Offset < 0 {
+		return c, fmt.Errorf("invalid cursor %q", s)
+	}
+	return c, nil
+}
+
+// renderDocTopic returns a file's body under a heading with its title
+func renderDocTopic(set *docSet, file string) string {
+	doc, err := set.Document(file)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("# %s\n\n%s\n\n", doc.DisplayTitle(), doc.Body)
+}
+
+// paginateDocs concatenates files starting at cursor until budget characters
+// are used. Files larger than the budget are split at line boundaries. It
+// returns the page and the cursor of the next page, or nil at the end.
+func paginateDocs(set *docSet, files []string, cursor docsCursor, budget int) (string, *docsCursor) {
+	var page strings.Builder
+
+	for i := cursor.File; i < len(files); i++ {
+		content := renderDocTopic(set, files[i])
+
+		offset := 0
+		if i == cursor.File {
+			offset = cursor.Offset
+		}
+		if offset >= len(content) {
+			continue
+		}
+
+		rest := content[offset:]
+		remaining := budget - page.Len()
+		if len(rest) <= remaining {
+			page.WriteString(rest)
+			continue
+		}
+
+		// Leave whole files for the next page unless nothing fits yet
+		if page.Len() > 0 && offset == 0 {
+			return page.String(), &docsCursor{File: i}
+		}
+
+		cut := strings.LastIndex(rest[:remaining], "\n")
+		if cut <= 0 {
+			cut = remaining
+			for cut > 0 && !utf8.RuneStart(rest[cut]) {
+				cut--
+			}
+		} else {
+			cut++
+		}
+
+		page.WriteString(rest[:cut])
+		return page.String(), &docsCursor{File: i, Offset: offset + cut}
+	}
+
+	return page.String(), nil
+}
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// docsRoot is the directory inside docsFS that holds one doc set per Base
+// Framework major version, e.g. md/v1 and md/v2
+const docsRoot = "md"
+
+// baseCoreModule is the module path Base projects require
+const baseCoreModule = "github.com/base-go/base-core"
+
+var versionDirPattern = regexp.MustCompile(`^v\d+$`)
+
+// docSet is one version of the documentation in one locale. File paths are
+// relative to the version directory, e.g. "docs/router.md", also for
+// translations.
+type docSet struct {
+	Version string
+	Locale  string
+	fsys    fs.FS
+
+	// translated holds only the files of a translation, translations the
+	// translated sets of an English set keyed by locale
+	translated   fs.FS
+	translations map[string]*docSet
+
+	indexOnce sync.Once
+	index     *searchIndex
+
+	linksOnce sync.Once
+	links     *linkGraph
+
+	symbolsOnce sync.Once
+	symbols     *symbolIndex
+}
+
+var (
+	docSetsMu sync.RWMutex
+
+	// docSets holds every doc set keyed by version
+	docSets map[string]*docSet
+
+	// defaultDocVersion is used when a request does not name a version
+	defaultDocVersion string
+)
+
+// initDocSets loads the doc sets, embedded and from DOCS_PATH, and picks the
+// default version from the base-core requirement of the project in the
+// working directory, falling back to the newest version
+func initDocSets() error {
+	sets, err := loadDocSets(docsFS, docsRoot, os.Getenv("DOCS_PATH"))
+	if err != nil {
+		return err
+	}
+
+	versions := sortVersions(sets)
+	defaultVersion := versions[len(versions)-1]
+
+	if cwd, err := os.Getwd(); err == nil {
+		if version := detectProjectBaseVersion(cwd); version != "" {
+			if _, ok := sets[version]; ok {
+				defaultVersion = version
+			} else {
+				log.Printf("Project uses base-core %s but no docs are available for it, using %s", version, defaultVersion)
+			}
+		}
+	}
+
+	docSetsMu.Lock()
+	docSets = sets
+	defaultDocVersion = defaultVersion
+	docSetsMu.Unlock()
+
+	return nil
+}
+
+// loadDocSets creates a doc set for every version directory under root in
+// fsys. When overlayDir is set its files take precedence: version directories
+// in it (v1, v2, ...) overlay or add that version, otherwise the directory
+// overlays the newest embedded version.
+func loadDocSets(fsys fs.FS, root, overlayDir string) (map[string]*docSet, error) {
+	layers := make(map[string][]fs.FS)
+
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !versionDirPattern.MatchString(entry.Name()) {
+			continue
+		}
+		sub, err := fs.Sub(fsys, path.Join(root, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		layers[entry.Name()] = []fs.FS{sub}
+	}
+
+	if overlayDir != "" {
+		overlays, err := overlayLayers(overlayDir, layers)
+		if err != nil {
+			return nil, err
+		}
+		for version, overlay := range overlays {
+			layers[version] = append([]fs.FS{overlay}, layers[version]...)
+		}
+	}
+
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("no versioned doc sets found in %s", root)
+	}
+
+	sets := make(map[string]*docSet, len(layers))
+	for version, fsyss := range layers {
+		set := &docSet{Version: version, Locale: defaultLocale, fsys: newOverlayFS(fsyss...)}
+		if err := set.loadTranslations(); err != nil {
+			return nil, fmt.Errorf("failed to read translations of %s: %w", version, err)
+		}
+		sets[version] = set
+	}
+	return sets, nil
+}
+
+// sortVersions returns the versions of sets, oldest first
+func sortVersions(sets map[string]*docSet) []string {
+	versions := make([]string, 0, len(sets))
+	for version := range sets {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versionNumber(versions[i]) < versionNumber(versions[j])
+	})
+	return versions
+}
+
+// defaultDocSet returns the doc set used when no version is requested
+func defaultDocSet() *docSet {
+	docSetsMu.RLock()
+	defer docSetsMu.RUnlock()
+	return docSets[defaultDocVersion]
+}
+
+// docVersions returns the available versions, oldest first
+func docVersions() []string {
+	docSetsMu.RLock()
+	defer docSetsMu.RUnlock()
+	return sortVersions(docSets)
+}
+
+// normalizeDocVersion maps "2", "v2" or "v2.1.7" to the doc set name "v2"
+func normalizeDocVersion(version string) string {
+	version = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+	major, _, _ := strings.Cut(version, ".")
+	if _, err := strconv.Atoi(major); err != nil {
+		return ""
+	}
+	return "v" + major
+}
+
+// getDocSet returns the doc set for version, or the default set when version is empty
+func getDocSet(version string) (*docSet, error) {
+	if strings.TrimSpace(version) == "" {
+		return defaultDocSet(), nil
+	}
+
+	docSetsMu.RLock()
+	set, ok := docSets[normalizeDocVersion(version)]
+	docSetsMu.RUnlock()
+	if ok {
+		return set, nil
+	}
+	return nil, fmt.Errorf("documentation version %q not available. Available versions: %s", version, strings.Join(docVersions(), ", "))
+}
+
+// docSetFromRequest returns the doc set named by a tool call's version and
+// locale arguments
+func docSetFromRequest(request mcp.CallToolRequest) (*docSet, error) {
+	set, err := getDocSet(request.GetString("version", ""))
+	if err != nil {
+		return nil, err
+	}
+	return set.Localized(request.GetString("locale", "")), nil
+}
+
+// withVersionArg declares the optional version argument shared by doc tools
+func withVersionArg() mcp.ToolOption {
+	return mcp.WithString("version",
+		mcp.Description("Base Framework version of the docs, e.g. v2 (defaults to the version required by the current project, or the latest)"),
+	)
+}
+
+// detectProjectBaseVersion walks up from dir to the nearest go.mod and returns
+// the major version of base-core it requires, e.g. "v2", or "" if none
+func detectProjectBaseVersion(dir string) string {
+	for {
+		if version, ok := goModBaseVersion(filepath.Join(dir, "go.mod")); ok {
+			return version
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// goModBaseVersion reads a go.mod file and reports whether it exists, along
+// with the base-core major version it requires. Only require directives
+// count, so replace and exclude lines never match.
+func goModBaseVersion(goModPath string) (string, bool) {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	inRequire := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequire && fields[0] == ")":
+			inRequire = false
+			continue
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+			continue
+		case fields[0] == "require":
+			fields = fields[1:]
+		case !inRequire:
+			continue
+		}
+
+		if len(fields) < 2 || strings.Contains(line, "=>") {
+			continue
+		}
+		if version, ok := baseCoreVersion(fields[0], fields[1]); ok {
+			return version, true
+		}
+	}
+
+	return "", true
+}
+
+// baseCoreVersion reports whether modulePath is base-core or one of its
+// major version paths, e.g. github.com/base-go/base-core/v3, and returns the
+// major version
+func baseCoreVersion(modulePath, version string) (string, bool) {
+	if modulePath == baseCoreModule {
+		return normalizeDocVersion(version), true
+	}
+
+	suffix, ok := strings.CutPrefix(modulePath, baseCoreModule+"/")
+	if !ok || !strings.HasPrefix(suffix, "v") {
+		return "", false
+	}
+	if _, err := strconv.Atoi(suffix[1:]); err != nil {
+		return "", false
+	}
+	return suffix, true
+}
+
+// Name identifies the set in messages, e.g. "v2" or "v2/fr"
+func (s *docSet) Name() string {
+	if s.Locale == defaultLocale {
+		return s.Version
+	}
+	return s.Version + "/" + s.Locale
+}
+
+// Files returns every markdown file in the set, leaving out the translation
+// directories
+func (s *docSet) Files() []string {
+	var files []string
+
+	fs.WalkDir(s.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() && isLocaleDir(p) {
+			return fs.SkipDir
+		}
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, ".md") {
+			return nil
+		}
+		files = append(files, p)
+		return nil
+	})
+
+	sort.Strings(files)
+	return files
+}
+
+// Resolve maps a user supplied name such as "websocket", "websocket.md" or
+// "docs/websocket.md" to a file in the set
+func (s *docSet) Resolve(name string) (string, bool) {
+	name = strings.TrimSpace(strings.ToLower(name))
+	name = strings.TrimPrefix(name, "/")
+	name = strings.TrimSuffix(name, ".md")
+	if name == "" {
+		return "", false
+	}
+
+	candidates := []string{
+		name + ".md",
+		"docs/" + name + ".md",
+	}
+
+	files := s.Files()
+	for _, candidate := range candidates {
+		for _, file := range files {
+			if strings.ToLower(file) == candidate {
+				return file, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// ReadFile returns the raw content of a file in the set
+func (s *docSet) ReadFile(file string) ([]byte, error) {
+	content, err := fs.ReadFile(s.fsys, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s (%s): %w", file, s.Name(), err)
+	}
+	return content, nil
+}
+
+// Document reads and parses a file in the set
+func (s *docSet) Document(file string) (*document, error) {
+	content, err := s.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, _ := parseDocument(file, string(content))
+	return doc, nil
+}
+
+// Outline resolves a file name and parses its heading tree
+func (s *docSet) Outline(name string) (*docOutline, error) {
+	file, ok := s.Resolve(name)
+	if !ok {
+		return nil, fmt.Errorf("documentation file %q not found. Available files: %s", name, strings.Join(s.Files(), ", "))
+	}
+
+	content, err := s.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	outline := parseOutline(file, string(content))
+	if doc, _ := parseDocument(file, string(content)); doc != nil {
+		outline.Meta = doc.Meta
+	}
+
+	return outline, nil
+}
+
+// Section returns the outline and heading trail of a section given a
+// reference such as "cli.md > Field Types Reference"
+func (s *docSet) Section(ref string) (*docOutline, []*docHeading, error) {
+	segments := splitHeadingPath(ref)
+	if len(segments) < 2 {
+		return nil, nil, fmt.Errorf("section reference %q needs a file and at least one heading", ref)
+	}
+
+	outline, err := s.Outline(segments[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trail := outline.Find(segments[1:])
+	if trail == nil {
+		return nil, nil, fmt.Errorf("section %q not found in %s", strings.Join(segments[1:], " > "), outline.File)
+	}
+
+	return outline, trail, nil
+}
+
+// Sections splits every file in the set into passages
+func (s *docSet) Sections() []docSection {
+	var sections []docSection
+
+	for _, file := range s.Files() {
+		content, err := s.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		doc, _ := parseDocument(file, string(content))
+		for _, section := range splitSections(file, string(content)) {
+			section.DocTitle = doc.DisplayTitle()
+			sections = append(sections, section)
+		}
+	}
+
+	return sections
+}
+
+// Index returns the set's search index, building it on first use
+func (s *docSet) Index() *searchIndex {
+	s.indexOnce.Do(func() {
+		s.index = newSearchIndex(s.Sections())
+	})
+	return s.index
+}
+
+// Links returns the set's link graph, building it on first use
+func (s *docSet) Links() *linkGraph {
+	s.linksOnce.Do(func() {
+		s.links = buildLinkGraph(s)
+	})
+	return s.links
+}
+
+// Symbols returns the set's symbol index, building it on first use
+func (s *docSet) Symbols() *symbolIndex {
+	s.symbolsOnce.Do(func() {
+		s.symbols = buildSymbolIndex(s)
+	})
+	return s.symbols
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGoModBaseVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		goMod string
+		want  string
+	}{
+		{
+			name:  "require line",
+			goMod: "module myapp\n\nrequire github.com/base-go/base-core v2.1.7\n",
+			want:  "v2",
+		},
+		{
+			name:  "require block",
+			goMod: "module myapp\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.1\n\tgithub.com/base-go/base-core v2.0.3 // indirect\n)\n",
+			want:  "v2",
+		},
+		{
+			name:  "major version path",
+			goMod: "module myapp\n\nrequire github.com/base-go/base-core/v3 v3.0.0\n",
+			want:  "v3",
+		},
+		{
+			name:  "replaced",
+			goMod: "module myapp\n\nrequire github.com/base-go/base-core v2.1.7\n\nreplace github.com/base-go/base-core => ../base-core\n",
+			want:  "v2",
+		},
+		{
+			name:  "replace block before require",
+			goMod: "module myapp\n\nreplace (\n\tgithub.com/base-go/base-core => ../base-core\n)\n\nrequire github.com/base-go/base-core v2.1.7\n",
+			want:  "v2",
+		},
+		{
+			name:  "replace only",
+			goMod: "module myapp\n\nreplace (\n\tgithub.com/base-go/base-core v2.1.7 => ../base-core\n)\n",
+		},
+		{
+			name:  "module with the same prefix",
+			goMod: "module myapp\n\nrequire github.com/base-go/base-core-extras v1.0.0\n",
+		},
+		{
+			name:  "subpackage module",
+			goMod: "module myapp\n\nrequire github.com/base-go/base-core/tools v1.0.0\n",
+		},
+		{
+			name:  "commented out",
+			goMod: "module myapp\n\n// require github.com/base-go/base-core v2.1.7\n",
+		},
+		{
+			name:  "no base-core",
+			goMod: "module myapp\n\nrequire github.com/gin-gonic/gin v1.9.1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "go.mod")
+			if err := os.WriteFile(path, []byte(tt.goMod), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			version, ok := goModBaseVersion(path)
+			if !ok {
+				t.Fatal("go.mod not found")
+			}
+			if version != tt.want {
+				t.Errorf("goModBaseVersion() = %q, want %q", version, tt.want)
+			}
+		})
+	}
+
+	if _, ok := goModBaseVersion(filepath.Join(t.TempDir(), "go.mod")); ok {
+		t.Error("goModBaseVersion() found a missing go.mod")
+	}
+}
+
+func TestIsBaseProjectReplaced(t *testing.T) {
+	dir := t.TempDir()
+	goMod := "module myapp\n\nreplace (\n\tgithub.com/base-go/base-core => ../base-core\n)\n\nrequire github.com/base-go/base-core v2.1.7\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if !isBaseProject(dir) {
+		t.Error("isBaseProject() rejected a project replacing base-core")
+	}
+}
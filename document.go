@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// docMeta is the YAML frontmatter of a documentation file. Doc pages only set
+// title and description; the home pages also carry a VitePress hero and
+// feature list.
+type docMeta struct {
+	Title       string       `yaml:"title" json:"title,omitempty"`
+	Description string       `yaml:"description" json:"description,omitempty"`
+	Layout      string       `yaml:"layout" json:"layout,omitempty"`
+	Hero        *docHero     `yaml:"hero" json:"hero,omitempty"`
+	Features    []docFeature `yaml:"features" json:"features,omitempty"`
+}
+
+type docHero struct {
+	Name    string          `yaml:"name" json:"name,omitempty"`
+	Text    string          `yaml:"text" json:"text,omitempty"`
+	Tagline string          `yaml:"tagline" json:"tagline,omitempty"`
+	Image   *docHeroImage   `yaml:"image" json:"image,omitempty"`
+	Actions []docHeroAction `yaml:"actions" json:"actions,omitempty"`
+}
+
+type docHeroImage struct {
+	Src string `yaml:"src" json:"src,omitempty"`
+	Alt string `yaml:"alt" json:"alt,omitempty"`
+}
+
+type docHeroAction struct {
+	Theme string `yaml:"theme" json:"theme,omitempty"`
+	Text  string `yaml:"text" json:"text,omitempty"`
+	Link  string `yaml:"link" json:"link,omitempty"`
+}
+
+type docFeature struct {
+	Icon    string `yaml:"icon" json:"icon,omitempty"`
+	Title   string `yaml:"title" json:"title,omitempty"`
+	Details string `yaml:"details" json:"details,omitempty"`
+	Link    string `yaml:"link" json:"link,omitempty"`
+}
+
+// document is a parsed documentation file. BodyLine is the 1-based line of
+// the raw file where Body starts.
+type document struct {
+	File           string
+	Meta           docMeta
+	HasFrontmatter bool
+	Body           string
+	BodyLine       int
+}
+
+// DisplayTitle returns the frontmatter title, falling back to the first
+// heading and then the file name
+func (d *document) DisplayTitle() string {
+	if d.Meta.Title != "" {
+		return d.Meta.Title
+	}
+	if d.Meta.Hero != nil && d.Meta.Hero.Name != "" {
+		return d.Meta.Hero.Name
+	}
+	for _, heading := range scanHeadings(splitLines(d.Body)) {
+		if heading.Title != "" {
+			return heading.Title
+		}
+	}
+	return strings.TrimSuffix(path.Base(d.File), ".md")
+}
+
+// DisplayDescription returns the frontmatter description, falling back to the hero tagline
+func (d *document) DisplayDescription() string {
+	if d.Meta.Description != "" {
+		return d.Meta.Description
+	}
+	if d.Meta.Hero != nil {
+		if d.Meta.Hero.Tagline != "" {
+			return d.Meta.Hero.Tagline
+		}
+		return d.Meta.Hero.Text
+	}
+	return ""
+}
+
+var yamlKeyLine = regexp.MustCompile(`^[A-Za-z_][\w-]*\s*:`)
+
+// frontmatterBlock locates a frontmatter block: a "---" first line, YAML
+// content, and a closing "---" or "..." line. A leading block whose content
+// does not look like YAML keys is a horizontal rule, not frontmatter.
+func frontmatterBlock(lines []string) (string, int, bool) {
+	if len(lines) == 0 || strings.TrimRight(lines[0], " \t") != "---" {
+		return "", 0, false
+	}
+
+	for i := 1; i < len(lines); i++ {
+		closing := strings.TrimRight(lines[i], " \t")
+		if closing != "---" && closing != "..." {
+			continue
+		}
+
+		block := lines[1:i]
+		for _, line := range block {
+			if yamlKeyLine.MatchString(line) {
+				return strings.Join(block, "\n"), i + 1, true
+			}
+		}
+		return "", 0, false
+	}
+
+	return "", 0, false
+}
+
+// frontmatterLineCount returns the number of leading lines taken up by a YAML
+// frontmatter block, or 0 when the file has none
+func frontmatterLineCount(lines []string) int {
+	_, n, _ := frontmatterBlock(lines)
+	return n
+}
+
+// parseDocument splits a markdown file into typed frontmatter and body. When
+// the frontmatter is not valid YAML the document is still returned, with
+// empty metadata, alongside the decode error.
+func parseDocument(file, content string) (*document, error) {
+	lines := splitLines(content)
+	doc := &document{File: file, BodyLine: 1}
+
+	block, n, ok := frontmatterBlock(lines)
+	doc.HasFrontmatter = ok
+
+	doc.BodyLine = n + 1
+	for doc.BodyLine <= len(lines) && strings.TrimSpace(lines[doc.BodyLine-1]) == "" {
+		doc.BodyLine++
+	}
+	doc.Body = strings.TrimSpace(strings.Join(lines[n:], "\n"))
+
+	if !ok {
+		return doc, nil
+	}
+	if err := yaml.Unmarshal([]byte(block), &doc.Meta); err != nil {
+		doc.Meta = docMeta{}
+		return doc, fmt.Errorf("invalid frontmatter in %s: %w", file, err)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import "testing"
+
+func TestParseDocument(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		hasFrontmatter bool
+		title          string
+		description    string
+		body           string
+		bodyLine       int
+		wantErr        bool
+	}{
+		{
+			name:           "frontmatter",
+			content:        "---\ntitle: Router\ndescription: HTTP routing\n---\n\n# Routing\nText\n",
+			hasFrontmatter: true,
+			title:          "Router",
+			description:    "HTTP routing",
+			body:           "# Routing\nText",
+			bodyLine:       6,
+		},
+		{
+			name:           "yaml document end marker",
+			content:        "---\ntitle: Router\n...\n# Routing\n",
+			hasFrontmatter: true,
+			title:          "Router",
+			body:           "# Routing",
+			bodyLine:       4,
+		},
+		{
+			name:     "no frontmatter",
+			content:  "# Storage\nFiles\n",
+			title:    "Storage",
+			body:     "# Storage\nFiles",
+			bodyLine: 1,
+		},
+		{
+			name:     "leading horizontal rule",
+			content:  "---\nSome intro prose.\n---\n# Events\n",
+			title:    "Events",
+			body:     "---\nSome intro prose.\n---\n# Events",
+			bodyLine: 1,
+		},
+		{
+			name:     "unclosed rule",
+			content:  "---\n# Events\n",
+			title:    "Events",
+			body:     "---\n# Events",
+			bodyLine: 1,
+		},
+		{
+			name:           "hero",
+			content:        "---\nlayout: home\nhero:\n  name: Base\n  tagline: Build APIs fast\n---\nWelcome\n",
+			hasFrontmatter: true,
+			title:          "Base",
+			description:    "Build APIs fast",
+			body:           "Welcome",
+			bodyLine:       7,
+		},
+		{
+			name:           "invalid yaml",
+			content:        "---\ntitle: [unclosed\n---\n# Broken\n",
+			hasFrontmatter: true,
+			title:          "Broken",
+			body:           "# Broken",
+			bodyLine:       4,
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parseDocument("docs/test.md", tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error: %v", err, tt.wantErr)
+			}
+			if doc.HasFrontmatter != tt.hasFrontmatter {
+				t.Errorf("HasFrontmatter = %v, want %v", doc.HasFrontmatter, tt.hasFrontmatter)
+			}
+			if got := doc.DisplayTitle(); got != tt.title {
+				t.Errorf("DisplayTitle() = %q, want %q", got, tt.title)
+			}
+			if got := doc.DisplayDescription(); got != tt.description {
+				t.Errorf("DisplayDescription() = %q, want %q", got, tt.description)
+			}
+			if doc.Body != tt.body {
+				t.Errorf("Body = %q, want %q", doc.Body, tt.body)
+			}
+			if doc.BodyLine != tt.bodyLine {
+				t.Errorf("BodyLine = %d, want %d", doc.BodyLine, tt.bodyLine)
+			}
+		})
+	}
+}
+
+func TestFrontmatterLineCount(t *testing.T) {
+	tests := []struct {
+		content string
+		want    int
+	}{
+		{"---\ntitle: A\n---\nbody", 3},
+		{"---\nnot yaml\n---\nbody", 0},
+		{"# Title\n---\ntitle: A\n---", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := frontmatterLineCount(splitLines(tt.content)); got != tt.want {
+			t.Errorf("frontmatterLineCount(%q) = %d, want %d", tt.content, got, tt.want)
+		}
+	}
+}
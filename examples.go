@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// exampleTopic tags code examples that come from one of Files or whose code
+// contains one of Keywords. Languages restricts keyword matches to blocks in
+// those languages.
+type exampleTopic struct {
+	Name      string
+	Files     []string
+	Keywords  []string
+	Languages []string
+}
+
+var exampleTopics = []exampleTopic{
+	{Name: "routing", Files: []string{"router"}, Keywords: []string{"router.", "RouterGroup", ".GET(\"", ".POST(\"", ".PUT(\"", ".DELETE(\"", ".Group(\""}, Languages: []string{"go"}},
+	{Name: "auth", Files: []string{"auth"}, Keywords: []string{"authorization.", "JWT", "OAUTH", "/api/auth/"}},
+	{Name: "storage", Files: []string{"storage"}, Keywords: []string{"storage.", "Storage.", "Attachment", "STORAGE_"}},
+	{Name: "websocket", Files: []string{"websocket"}, Keywords: []string{"websocket", "WebSocket", "ws://", "WS_"}},
+	{Name: "cli", Files: []string{"cli", "installation"}, Keywords: []string{"base "}, Languages: []string{"bash"}},
+	{Name: "email", Files: []string{"email"}, Keywords: []string{"email.", "EMAIL_"}},
+	{Name: "events", Files: []string{"emitter"}, Keywords: []string{"Emitter.", "emitter."}},
+	{Name: "logging", Files: []string{"logger"}, Keywords: []string{"logger.", "LOG_"}},
+	{Name: "scheduler", Files: []string{"scheduler"}, Keywords: []string{"scheduler.", "Scheduler"}},
+	{Name: "validation", Files: []string{"validator"}, Keywords: []string{"validator.", "binding:\"", "validate:\""}},
+	{Name: "translation", Files: []string{"translation"}, Keywords: []string{"translation."}},
+	{Name: "middleware", Files: []string{"middleware"}, Keywords: []string{"middleware.", "MIDDLEWARE_"}},
+	{Name: "configuration", Files: []string{"configuration"}, Languages: []string{"env"}},
+	{Name: "models", Keywords: []string{"gorm.", "gorm:\"", "models."}, Languages: []string{"go"}},
+}
+
+// codeExample is a code block of the docs, tagged with topics
+type codeExample struct {
+	ID          string   `json:"id"`
+	File        string   `json:"file"`
+	Heading     string   `json:"heading,omitempty"`
+	Language    string   `json:"language"`
+	Topics      []string `json:"topics"`
+	StartLine   int      `json:"start_line"`
+	EndLine     int      `json:"end_line"`
+	Description string   `json:"description,omitempty"`
+	Code        string   `json:"code"`
+}
+
+// exampleTopicsFor returns the topics of a code block
+func exampleTopicsFor(block codeBlock) []string {
+	name := strings.TrimSuffix(path.Base(block.File), ".md")
+
+	var topics []string
+	for _, topic := range exampleTopics {
+		if containsString(topic.Files, name) {
+			topics = append(topics, topic.Name)
+			continue
+		}
+		if len(topic.Languages) > 0 && !containsString(topic.Languages, block.Lang) {
+			continue
+		}
+		if len(topic.Keywords) == 0 {
+			topics = append(topics, topic.Name)
+			continue
+		}
+		for _, keyword := range topic.Keywords {
+			if strings.Contains(block.Code, keyword) {
+				topics = append(topics, topic.Name)
+				break
+			}
+		}
+	}
+
+	if len(topics) == 0 {
+		topics = []string{"general"}
+	}
+	return topics
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Examples returns every non-empty code block of the set as a code example
+func (s *docSet) Examples() []codeExample {
+	var examples []codeExample
+	for _, block := range s.CodeBlocks() {
+		if strings.TrimSpace(block.Code) == "" {
+			continue
+		}
+		examples = append(examples, codeExample{
+			ID:          fmt.Sprintf("%s:%d", block.File, block.StartLine),
+			File:        block.File,
+			Heading:     strings.Join(block.Path, " > "),
+			Language:    block.Lang,
+			Topics:      exampleTopicsFor(block),
+			StartLine:   block.StartLine,
+			EndLine:     block.EndLine,
+			Description: block.Context,
+			Code:        block.Code,
+		})
+	}
+	return examples
+}
+
+// exampleFacets counts examples per topic and per language
+func exampleFacets(examples []codeExample) (map[string]int, map[string]int) {
+	topics := make(map[string]int)
+	languages := make(map[string]int)
+	for _, example := range examples {
+		for _, topic := range example.Topics {
+			topics[topic]++
+		}
+		languages[example.Language]++
+	}
+	return topics, languages
+}
+
+// formatFacets renders counts as "name (n), ..." sorted by name
+func formatFacets(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%d)", name, counts[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func handleBaseExamples(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	topic := strings.ToLower(strings.TrimSpace(request.GetString("topic", "")))
+	language := strings.ToLower(strings.TrimSpace(request.GetString("language", "")))
+	if lang, ok := codeLanguages[language]; ok {
+		language = lang
+	}
+	query := strings.ToLower(strings.TrimSpace(request.GetString("query", "")))
+
+	limit := request.GetInt("limit", 10)
+	switch {
+	case limit < 1:
+		limit = 10
+	case limit > 50:
+		limit = 50
+	}
+	offset := request.GetInt("offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	set, err := docSetFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	all := set.Examples()
+	var matched []codeExample
+	for _, example := range all {
+		if topic != "" && !containsString(example.Topics, topic) {
+			continue
+		}
+		if language != "" && example.Language != language {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(example.Code+"\n"+example.Heading+"\n"+example.Description), query) {
+			continue
+		}
+		matched = append(matched, example)
+	}
+
+	topics, languages := exampleFacets(all)
+	if len(matched) == 0 {
+		return mcp.NewToolResultErrorf("No code examples match. Topics: %s. Languages: %s", formatFacets(topics), formatFacets(languages)), nil
+	}
+
+	total := len(matched)
+	if offset > total {
+		offset = total
+	}
+	page := matched[offset:]
+	if len(page) > limit {
+		page = page[:limit]
+	}
+
+	next := offset + len(page)
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%d code examples", total)
+	switch {
+	case len(page) == 0:
+		fmt.Fprintf(&text, " (none past offset %d)", offset)
+	case next < total:
+		fmt.Fprintf(&text, " (showing %d-%d, pass offset=%d for more)", offset+1, next, next)
+	case len(page) < total:
+		fmt.Fprintf(&text, " (showing %d-%d)", offset+1, next)
+	}
+	text.WriteString("\n\n")
+	for _, example := range page {
+		fmt.Fprintf(&text, "### %s — %s [%s]\n", example.ID, example.Heading, strings.Join(example.Topics, ", "))
+		if example.Description != "" {
+			fmt.Fprintf(&text, "%s\n", example.Description)
+		}
+		fmt.Fprintf(&text, "```%s\n%s\n```\n\n", example.Language, example.Code)
+	}
+
+	result := map[string]any{
+		"version":   set.Version,
+		"total":     total,
+		"offset":    offset,
+		"examples":  page,
+		"topics":    topics,
+		"languages": languages,
+	}
+	if next < total {
+		result["next_offset"] = next
+	}
+
+	return mcp.NewToolResultStructured(result, strings.TrimSpace(text.String())), nil
+}
@@ -1,28 +1,99 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 )
 
-// ExecutorService handles execution of Base CLI commands
+// Statuses of a Base CLI command that did not complete successfully
+const (
+	CommandFailed    = "failed"
+	CommandCancelled = "cancelled"
+	CommandTimedOut  = "timed out"
+)
+
+// commandWaitDelay is how long a killed command's output pipes are waited
+// for, in case a process outside its group still holds them
+const commandWaitDelay = 5 * time.Second
+
+// commandTimeouts bounds how long each Base CLI command may run. Commands
+// not listed use defaultCommandTimeout.
+var commandTimeouts = map[string]time.Duration{
+	"generate": 2 * time.Minute,
+	"destroy":  time.Minute,
+	"new":      5 * time.Minute,
+	"docs":     5 * time.Minute,
+}
+
+const defaultCommandTimeout = 5 * time.Minute
+
+// commandTimeout returns the timeout of a Base CLI command
+func commandTimeout(command string) time.Duration {
+	if timeout, ok := commandTimeouts[command]; ok {
+		return timeout
+	}
+	return defaultCommandTimeout
+}
+
+// CommandError reports a Base CLI command that failed, was cancelled or
+// timed out, with the output it produced until then
+type CommandError struct {
+	Command []string
+	Status  string
+	Output  string
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *CommandError) Error() string {
+	command := strings.Join(e.Command, " ")
+	switch e.Status {
+	case CommandTimedOut:
+		if e.Timeout > 0 {
+			return fmt.Sprintf("%s timed out after %s", command, e.Timeout)
+		}
+		return fmt.Sprintf("%s timed out", command)
+	case CommandCancelled:
+		return fmt.Sprintf("%s was cancelled", command)
+	}
+	return fmt.Sprintf("%s failed: %v", command, e.Err)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ExecutorService handles execution of Base CLI commands. Every command runs
+// in an explicit directory, normally the root of a Base project.
 type ExecutorService struct {
 	basePath string
 	cmdPath  string
+
+	// buildMu guards builds, the CLI binaries built from cmd directories
+	// when no base executable is installed
+	buildMu sync.Mutex
+	builds  map[string]string
 }
 
 // NewExecutorService creates a new executor service
 func NewExecutorService() *ExecutorService {
 	// Try to find the base command in various locations
 	basePath := findBasePath()
-	cmdPath := findCmdPath()
+	cmdPath := findCmdPath(".")
 
 	return &ExecutorService{
 		basePath: basePath,
 		cmdPath:  cmdPath,
+		builds:   make(map[string]string),
 	}
 }
 
@@ -44,16 +115,19 @@ func findBasePath() string {
 
 	for _, candidate := range candidates {
 		if _, err := os.Stat(candidate); err == nil {
+			if abs, err := filepath.Abs(candidate); err == nil {
+				return abs
+			}
 			return candidate
 		}
 	}
 
-	// Default to assuming base is in PATH
-	return "base"
+	return ""
 }
 
-// findCmdPath attempts to locate the cmd directory for the Base CLI
-func findCmdPath() string {
+// findCmdPath attempts to locate the cmd directory of the Base CLI sources
+// relative to dir
+func findCmdPath(dir string) string {
 	candidates := []string{
 		"../cmd",
 		"./cmd",
@@ -61,7 +135,11 @@ func findCmdPath() string {
 	}
 
 	for _, candidate := range candidates {
-		if stat, err := os.Stat(candidate); err == nil && stat.IsDir() {
+		candidate = filepath.Join(dir, candidate)
+		if stat, err := os.Stat(filepath.Join(candidate, "main.go")); err == nil && !stat.IsDir() {
+			if abs, err := filepath.Abs(candidate); err == nil {
+				return abs
+			}
 			return candidate
 		}
 	}
@@ -69,16 +147,19 @@ func findCmdPath() string {
 	return ""
 }
 
-// ExecuteGenerate executes the base generate command
-func (e *ExecutorService) ExecuteGenerate(name string, fields []string) (string, error) {
+// ExecuteGenerate executes the base generate command in the project at dir
+func (e *ExecutorService) ExecuteGenerate(ctx context.Context, dir, name string, fields []string) (string, error) {
 	args := []string{"generate", name}
 	args = append(args, fields...)
 
-	return e.executeBaseCommand(args...)
+	return e.executeBaseCommand(ctx, dir, "", args...)
 }
 
-// ExecuteStart executes the base start command
-func (e *ExecutorService) ExecuteStart(reload, docs bool) (string, error) {
+// ExecuteStart starts the base start command in the project at dir in the
+// background, writing its output to output, and returns the running
+// command. ctx only bounds building the CLI; the caller waits for or stops
+// the command.
+func (e *ExecutorService) ExecuteStart(ctx context.Context, dir string, reload, docs bool, output io.Writer) (*exec.Cmd, error) {
 	args := []string{"start"}
 
 	if reload {
@@ -88,72 +169,264 @@ func (e *ExecutorService) ExecuteStart(reload, docs bool) (string, error) {
 		args = append(args, "-d")
 	}
 
-	return e.executeBaseCommand(args...)
-}
+	binary, err := e.resolveCLI(ctx, defaultCommandTimeout, dir)
+	if err != nil {
+		return nil, err
+	}
 
-// ExecuteNew executes the base new command
-func (e *ExecutorService) ExecuteNew(name, path string) (string, error) {
-	args := []string{"new", name}
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = dir
+	cmd.Stdout = output
+	cmd.Stderr = output
+	setProcessGroup(cmd)
 
-	if path != "" {
-		args = append(args, "--path", path)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start base start: %w", err)
 	}
+	return cmd, nil
+}
 
-	return e.executeBaseCommand(args...)
+// ExecuteNew executes the base new command, creating the project in a new
+// directory under dir
+func (e *ExecutorService) ExecuteNew(ctx context.Context, dir, name string) (string, error) {
+	return e.executeBaseCommand(ctx, dir, "", "new", name)
 }
 
-// ExecuteDestroy executes the base destroy command
-func (e *ExecutorService) ExecuteDestroy(name string) (string, error) {
-	return e.executeBaseCommand("destroy", name)
+// ExecuteDestroy executes the base destroy command in the project at dir,
+// answering its confirmation prompt: callers confirm with the user first
+func (e *ExecutorService) ExecuteDestroy(ctx context.Context, dir, name string) (string, error) {
+	return e.executeBaseCommand(ctx, dir, "y\n", "destroy", name)
 }
 
-// ExecuteDocs executes the base docs command
-func (e *ExecutorService) ExecuteDocs() (string, error) {
-	return e.executeBaseCommand("docs")
+// ExecuteUpdate executes the base update command, replacing the core
+// directory of the project at dir with the latest Base core
+func (e *ExecutorService) ExecuteUpdate(ctx context.Context, dir string) (string, error) {
+	return e.executeBaseCommand(ctx, dir, "", "update")
+}
+
+// ExecuteDocs executes the base docs command in the project at dir
+func (e *ExecutorService) ExecuteDocs(ctx context.Context, dir, output string, static bool) (string, error) {
+	args := []string{"docs"}
+
+	if output != "" {
+		args = append(args, "--output", output)
+	}
+	if !static {
+		args = append(args, "--no-static")
+	}
+
+	return e.executeBaseCommand(ctx, dir, "", args...)
 }
 
-// executeBaseCommand executes a base command with the given arguments
-func (e *ExecutorService) executeBaseCommand(args ...string) (string, error) {
+// executeBaseCommand executes a base command with the given arguments in
+// dir, with input on its stdin. The command is killed when ctx ends or its
+// timeout runs out; the output up to then is returned with a
+// *CommandError. The changes of mutating commands are recorded in the
+// project's journal.
+func (e *ExecutorService) executeBaseCommand(ctx context.Context, dir, input string, args ...string) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("no directory to run base %s in", strings.Join(args, " "))
+	}
+
+	timeout := commandTimeout(args[0])
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	binary, err := e.resolveCLI(ctx, timeout, dir)
+	if err != nil {
+		return "", err
+	}
+
+	run := func() (string, error) {
+		return runCommand(ctx, timeout, dir, input, binary, args...)
+	}
+	paths, ok := journaledCommands[args[0]]
+	if !ok {
+		return run()
+	}
+
+	// base new creates the project in a new directory, whose journal
+	// then starts with it
+	root := dir
+	if args[0] == "new" {
+		root = filepath.Join(dir, args[1])
+	}
+	return runJournaled(root, paths, append([]string{"base"}, args...), run)
+}
+
+// resolveCLI returns the Base CLI to run for the project at dir: the
+// installed base executable, or else one built from the CLI sources next to
+// the project or the server. Running the sources with go run would make
+// their directory the working directory.
+func (e *ExecutorService) resolveCLI(ctx context.Context, timeout time.Duration, dir string) (string, error) {
 	// Try using the base CLI if available
 	if e.basePath != "" {
-		cmd := exec.Command(e.basePath, args...)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return "", fmt.Errorf("base command failed: %v\nOutput: %s", err, string(output))
-		}
-		return string(output), nil
+		return e.basePath, nil
 	}
 
-	// Fallback to direct Go execution if cmd path is available
-	if e.cmdPath != "" {
-		return e.executeGoDirect(args...)
+	// Fallback to building the CLI from source
+	cmdPath := findCmdPath(dir)
+	if cmdPath == "" {
+		cmdPath = e.cmdPath
+	}
+	if cmdPath != "" {
+		return e.buildCLI(ctx, timeout, cmdPath)
 	}
 
 	return "", fmt.Errorf("base CLI not found - please install Base CLI or run from Base project directory")
 }
 
-// executeGoDirect executes Base CLI commands directly using go run
-func (e *ExecutorService) executeGoDirect(args ...string) (string, error) {
-	mainGo := filepath.Join(e.cmdPath, "main.go")
+// buildCLI builds the Base CLI in cmdPath once and returns the binary
+func (e *ExecutorService) buildCLI(ctx context.Context, timeout time.Duration, cmdPath string) (string, error) {
+	e.buildMu.Lock()
+	defer e.buildMu.Unlock()
+
+	if binary, ok := e.builds[cmdPath]; ok {
+		return binary, nil
+	}
+
+	mainGo := filepath.Join(cmdPath, "main.go")
 
 	// Check if main.go exists
 	if _, err := os.Stat(mainGo); os.IsNotExist(err) {
 		return "", fmt.Errorf("base CLI main.go not found at %s", mainGo)
 	}
 
-	// Prepare go run command
-	goArgs := []string{"run", mainGo}
-	goArgs = append(goArgs, args...)
+	buildDir, err := os.MkdirTemp("", "base-cli-")
+	if err != nil {
+		return "", err
+	}
+	binary := filepath.Join(buildDir, "base")
 
-	cmd := exec.Command("go", goArgs...)
-	cmd.Dir = e.cmdPath
+	if _, err := runCommand(ctx, timeout, cmdPath, "", "go", "build", "-o", binary, "."); err != nil {
+		os.RemoveAll(buildDir)
+		return "", err
+	}
 
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("go run failed: %v\nOutput: %s", err, string(output))
+	e.builds[cmdPath] = binary
+	return binary, nil
+}
+
+// commandOutputKey is the context key of the commandOutput receiving the
+// output lines of running commands
+type commandOutputKey struct{}
+
+// commandOutput receives the output of a command line by line while it runs
+type commandOutput interface {
+	// Line receives a line of output. redraw is true for a line ended by a
+	// carriage return alone, such as a progress bar, which the next line
+	// replaces.
+	Line(line string, redraw bool)
+
+	// Flush is called once the command has exited
+	Flush()
+}
+
+// withCommandOutput returns a context under which runCommand passes every
+// output line to output while the command runs
+func withCommandOutput(ctx context.Context, output commandOutput) context.Context {
+	return context.WithValue(ctx, commandOutputKey{}, output)
+}
+
+// lineWriter collects the output of a command and passes each line to out
+// as soon as it is complete. A carriage return not followed by a newline
+// ends a redrawn line.
+type lineWriter struct {
+	output  bytes.Buffer
+	partial []byte
+	cr      bool
+	out     commandOutput
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.output.Write(p)
+	if w.out == nil {
+		return len(p), nil
+	}
+
+	for _, c := range p {
+		if w.cr {
+			w.cr = false
+			if c == '\n' {
+				w.emit(false)
+				continue
+			}
+			w.emit(true)
+		}
+		switch c {
+		case '\r':
+			w.cr = true
+		case '\n':
+			w.emit(false)
+		default:
+			w.partial = append(w.partial, c)
+		}
 	}
+	return len(p), nil
+}
 
-	return string(output), nil
+func (w *lineWriter) emit(redraw bool) {
+	if len(w.partial) > 0 {
+		w.out.Line(string(w.partial), redraw)
+	}
+	w.partial = w.partial[:0]
+}
+
+// flush passes on the unterminated last line, if any, once the command
+// has exited
+func (w *lineWriter) flush() {
+	if w.out == nil {
+		return
+	}
+	w.cr = false
+	w.emit(false)
+	w.out.Flush()
+}
+
+// runCommand runs a program in dir in its own process group, with input on
+// its stdin, and returns its combined output, streaming its lines to the
+// commandOutput set with withCommandOutput. When ctx ends the whole group
+// is killed and the output so far is returned with a *CommandError;
+// timeout is only used to report a deadline as a timeout.
+func runCommand(ctx context.Context, timeout time.Duration, dir, input, name string, args ...string) (string, error) {
+	output := &lineWriter{}
+	output.out, _ = ctx.Value(commandOutputKey{}).(commandOutput)
+
+	// A single writer for both streams keeps their lines in order
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if input != "" {
+		cmd.Stdin = strings.NewReader(input)
+	}
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessGroup(cmd) }
+	cmd.WaitDelay = commandWaitDelay
+
+	err := cmd.Run()
+	output.flush()
+	if err == nil {
+		return output.output.String(), nil
+	}
+
+	commandErr := &CommandError{
+		Command: append([]string{filepath.Base(name)}, args...),
+		Status:  CommandFailed,
+		Output:  output.output.String(),
+		Err:     err,
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		commandErr.Status = CommandTimedOut
+		commandErr.Timeout = timeout
+	case ctx.Err() != nil:
+		commandErr.Status = CommandCancelled
+	}
+	return commandErr.Output, commandErr
 }
 
 // IsBaseAvailable checks if Base CLI is available
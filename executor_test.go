@@ -0,0 +1,85 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// recordedOutput records the lines passed to a commandOutput
+type recordedOutput struct {
+	lines   []string
+	flushed bool
+}
+
+func (r *recordedOutput) Line(line string, redraw bool) {
+	if redraw {
+		line = "~" + line
+	}
+	r.lines = append(r.lines, line)
+}
+
+func (r *recordedOutput) Flush() {
+	r.flushed = true
+}
+
+func TestLineWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   []string
+	}{
+		{
+			name:   "lines",
+			writes: []string{"one\ntwo\n"},
+			want:   []string{"one", "two"},
+		},
+		{
+			name:   "split writes",
+			writes: []string{"on", "e\nt", "wo"},
+			want:   []string{"one", "two"},
+		},
+		{
+			name:   "crlf",
+			writes: []string{"one\r\ntwo\r", "\n"},
+			want:   []string{"one", "two"},
+		},
+		{
+			name:   "progress bar",
+			writes: []string{"10%\r50%\r", "100%\ndone\n"},
+			want:   []string{"~10%", "~50%", "100%", "done"},
+		},
+		{
+			name:   "trailing redraw",
+			writes: []string{"10%\r50%\r"},
+			want:   []string{"~10%", "50%"},
+		},
+		{
+			name:   "blank lines",
+			writes: []string{"\n\r\none\n\n"},
+			want:   []string{"one"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &recordedOutput{}
+			w := &lineWriter{out: out}
+			written := ""
+			for _, p := range tt.writes {
+				w.Write([]byte(p))
+				written += p
+			}
+			w.flush()
+
+			if !reflect.DeepEqual(out.lines, tt.want) {
+				t.Errorf("lines = %q, want %q", out.lines, tt.want)
+			}
+			if !out.flushed {
+				t.Error("the output was not flushed")
+			}
+			if w.output.String() != written {
+				t.Errorf("output = %q, want %q", w.output.String(), written)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fieldType is one entry of the `base g` field type reference in cli.md
+type fieldType struct {
+	Type       string `json:"type"`
+	Category   string `json:"category"`
+	Syntax     string `json:"syntax"`
+	GoType     string `json:"go_type"`
+	DBType     string `json:"db_type,omitempty"`
+	UseCase    string `json:"use_case,omitempty"`
+	Validation string `json:"validation,omitempty"`
+	Example    string `json:"example"`
+}
+
+// parseFieldTypes reads the field type tables from cli.md. The tables are
+// flattened to one cell per line: a header row starting with "CLI Type" or
+// "Relationship" and ending with "Example", followed by the rows.
+func parseFieldTypes(set *docSet) ([]fieldType, error) {
+	outline, trail, err := set.Section("cli.md > Field Types Reference")
+	if err != nil {
+		return nil, err
+	}
+
+	var types []fieldType
+	for _, table := range trail[len(trail)-1].Children {
+		types = append(types, parseFieldTable(table.Title, outline.Lines[table.StartLine:table.EndLine])...)
+	}
+
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no field types found in %s", outline.File)
+	}
+	return types, nil
+}
+
+func parseFieldTable(category string, lines []string) []fieldType {
+	var cells []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			cells = append(cells, line)
+		}
+	}
+
+	start := -1
+	for i, cell := range cells {
+		if cell == "CLI Type" || cell == "Relationship" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+
+	var columns []string
+	for _, cell := range cells[start:] {
+		columns = append(columns, cell)
+		if cell == "Example" {
+			break
+		}
+	}
+
+	var types []fieldType
+	rows := cells[start+len(columns):]
+	for i := 0; i+len(columns) <= len(rows); i += len(columns) {
+		ft := fieldType{Category: category}
+		for j, column := range columns {
+			value := rows[i+j]
+			switch column {
+			case "CLI Type":
+				ft.Type = value
+				ft.Syntax = "field:" + value
+			case "Relationship":
+				ft.UseCase = value
+			case "Syntax":
+				ft.Syntax = value
+				if parts := strings.Split(value, ":"); len(parts) == 3 {
+					ft.Type = parts[1]
+				}
+			case "Go Type":
+				ft.GoType = value
+			case "Database":
+				ft.DBType = value
+			case "Use Case", "Description":
+				ft.UseCase = value
+			case "Validation":
+				ft.Validation = value
+			case "Example":
+				ft.Example = value
+			}
+		}
+		types = append(types, ft)
+	}
+
+	return types
+}
+
+func handleBaseFieldTypes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	set, err := docSetFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	types, err := parseFieldTypes(set)
+	if err != nil {
+		return mcp.NewToolResultError("Error reading field types: " + err.Error()), nil
+	}
+
+	category := strings.ToLower(request.GetString("category", ""))
+	name := strings.ToLower(request.GetString("type", ""))
+
+	filtered := types[:0]
+	for _, ft := range types {
+		if category != "" && !strings.Contains(strings.ToLower(ft.Category), category) {
+			continue
+		}
+		if name != "" && strings.ToLower(ft.Type) != name {
+			continue
+		}
+		filtered = append(filtered, ft)
+	}
+
+	data, err := json.MarshalIndent(filtered, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{
+		"version":     set.Version,
+		"field_types": filtered,
+	}, string(data)), nil
+}
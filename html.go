@@ -110,7 +110,7 @@ const indexHTML = `<!DOCTYPE html>
 </html>`
 
 const docsIndexHTML = `<!DOCTYPE html>
-<html lang="en">
+<html lang="{{LANG}}">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
@@ -136,54 +136,7 @@ const docsIndexHTML = `<!DOCTYPE html>
     </div>
 
     <ul class="doc-list">
-        <li>
-            <a href="/docs/cli">cli.md</a>
-            <div class="description">Complete CLI command reference with examples and field types</div>
-        </li>
-        <li>
-            <a href="/docs/structure">structure.md</a>
-            <div class="description">Project structure and architecture overview</div>
-        </li>
-        <li>
-            <a href="/docs/application">application.md</a>
-            <div class="description">Application layer patterns and module development</div>
-        </li>
-        <li>
-            <a href="/docs/websocket">websocket.md</a>
-            <div class="description">Real-time WebSocket system and examples</div>
-        </li>
-        <li>
-            <a href="/docs/router">router.md</a>
-            <div class="description">HTTP router system and middleware</div>
-        </li>
-        <li>
-            <a href="/docs/storage">storage.md</a>
-            <div class="description">File storage system (local, S3, R2)</div>
-        </li>
-        <li>
-            <a href="/docs/emitter">emitter.md</a>
-            <div class="description">Event-driven communication system</div>
-        </li>
-        <li>
-            <a href="/docs/auth">auth.md</a>
-            <div class="description">Authentication and authorization patterns</div>
-        </li>
-        <li>
-            <a href="/docs/middleware">middleware.md</a>
-            <div class="description">HTTP middleware system and custom middleware</div>
-        </li>
-        <li>
-            <a href="/docs/logger">logger.md</a>
-            <div class="description">Structured logging system</div>
-        </li>
-        <li>
-            <a href="/docs/email">email.md</a>
-            <div class="description">Email system with multiple providers</div>
-        </li>
-        <li>
-            <a href="/docs/configuration">configuration.md</a>
-            <div class="description">Environment configuration and settings</div>
-        </li>
+{{DOCS_LIST}}
     </ul>
 
     <p style="text-align: center; margin-top: 40px; color: #6b7280;">
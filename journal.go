@@ -0,0 +1,648 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// journalDir is where a project keeps the snapshots and operation records
+// of the commands run on it, relative to its root
+const journalDir = ".base-mcp/journal"
+
+// maxJournalFileSize is the largest file snapshotted; bigger files are
+// neither recorded nor restored
+const maxJournalFileSize = 10 << 20
+
+// journalSourcePaths are the parts of a project that generate and destroy
+// change. Runtime data a dev server writes, such as SQLite databases, logs,
+// uploads and storage/, stays out of the snapshots so that undo does not
+// roll it back.
+var journalSourcePaths = []string{"app", "test", "go.mod", "go.sum"}
+
+// journaledCommands maps the Base CLI commands whose changes are recorded,
+// so that they can be undone, to the paths snapshotted around them. base new
+// creates the whole tree, so all of it is snapshotted; base update replaces
+// core/ and may bump the module requirements.
+var journaledCommands = map[string][]string{
+	"generate": journalSourcePaths,
+	"destroy":  journalSourcePaths,
+	"new":      {"."},
+	"update":   {"core", "go.mod", "go.sum"},
+}
+
+// journalSkipDirs are never snapshotted
+var journalSkipDirs = map[string]bool{
+	".git":         true,
+	".base-mcp":    true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// Actions of a journalChange
+const (
+	journalAdded    = "added"
+	journalModified = "modified"
+	journalDeleted  = "deleted"
+)
+
+// journalFile is a snapshotted file: the sha256 of its content, which names
+// its object in the journal, and its permissions
+type journalFile struct {
+	Hash string
+	Mode fs.FileMode
+}
+
+// journalChange is a file an operation added, modified or deleted, with the
+// objects of its content before and after
+type journalChange struct {
+	Path   string      `json:"path"`
+	Action string      `json:"action"`
+	Before string      `json:"before,omitempty"`
+	After  string      `json:"after,omitempty"`
+	Mode   fs.FileMode `json:"mode,omitempty"`
+}
+
+// journalOperation records a command run on the project and what it changed
+type journalOperation struct {
+	ID         int             `json:"id"`
+	Command    []string        `json:"command"`
+	Status     string          `json:"status"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Changes    []journalChange `json:"changes"`
+	UndoneAt   *time.Time      `json:"undone_at,omitempty"`
+}
+
+// Summary counts the changes by action, e.g. "3 added, 1 modified"
+func (op *journalOperation) Summary() string {
+	counts := make(map[string]int)
+	for _, change := range op.Changes {
+		counts[change.Action]++
+	}
+
+	var parts []string
+	for _, action := range []string{journalAdded, journalModified, journalDeleted} {
+		if counts[action] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[action], action))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// journal is the operation journal of the project at root. Contents are
+// stored once per sha256 under objects/, operations as numbered JSON files
+// under operations/.
+type journal struct {
+	root  string
+	ready bool
+}
+
+// journalLocks holds a mutex per project root, so that commands on the same
+// project do not interleave their snapshots
+var journalLocks sync.Map
+
+func lockJournal(root string) func() {
+	mu, _ := journalLocks.LoadOrStore(root, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+func (j *journal) path(elem ...string) string {
+	return filepath.Join(append([]string{j.root, filepath.FromSlash(journalDir)}, elem...)...)
+}
+
+func (j *journal) objectPath(hash string) string {
+	return j.path("objects", hash[:2], hash)
+}
+
+// init creates the journal directory, ignored by git, on first use
+func (j *journal) init() error {
+	if j.ready {
+		return nil
+	}
+	if err := os.MkdirAll(j.path("operations"), 0o755); err != nil {
+		return err
+	}
+
+	ignore := filepath.Join(j.root, ".base-mcp", ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		if err := os.WriteFile(ignore, []byte("*\n"), 0o644); err != nil {
+			return err
+		}
+	}
+
+	j.ready = true
+	return nil
+}
+
+// store hashes the file at path and adds its content to the objects
+func (j *journal) store(path string, info fs.FileInfo) (journalFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return journalFile{}, err
+	}
+	sum := sha256.Sum256(data)
+	file := journalFile{Hash: hex.EncodeToString(sum[:]), Mode: info.Mode().Perm()}
+
+	object := j.objectPath(file.Hash)
+	if _, err := os.Stat(object); err == nil {
+		return file, nil
+	}
+
+	if err := j.init(); err != nil {
+		return journalFile{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(object), 0o755); err != nil {
+		return journalFile{}, err
+	}
+	tmp := object + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return journalFile{}, err
+	}
+	return file, os.Rename(tmp, object)
+}
+
+// journalSnapshot is the state of the snapshotted files of a project, by
+// slash separated path. Skipped holds the files and directories that could
+// not be snapshotted, whose changes are not recorded.
+type journalSnapshot struct {
+	Files   map[string]journalFile
+	Skipped map[string]bool
+}
+
+// skips reports whether path or one of its directories was skipped
+func (s journalSnapshot) skips(path string) bool {
+	for ; path != "." && path != "/"; path = filepath.ToSlash(filepath.Dir(path)) {
+		if s.Skipped[path] {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshot stores the files under paths, relative to the project root. Files
+// that cannot be read or stored, or are too big, are skipped with a
+// warning; missing paths are empty.
+func (j *journal) snapshot(paths []string) journalSnapshot {
+	snapshot := journalSnapshot{
+		Files:   make(map[string]journalFile),
+		Skipped: make(map[string]bool),
+	}
+
+	skip := func(rel string, err error) {
+		log.Printf("Journal: not recording %s in %s: %v", rel, j.root, err)
+		snapshot.Skipped[rel] = true
+	}
+
+	for _, start := range paths {
+		start = filepath.Join(j.root, filepath.FromSlash(start))
+
+		filepath.WalkDir(start, func(path string, entry fs.DirEntry, err error) error {
+			rel, _ := filepath.Rel(j.root, path)
+			rel = filepath.ToSlash(rel)
+
+			if err != nil {
+				if path == start && errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				skip(rel, err)
+				if entry != nil && entry.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if entry.IsDir() {
+				if path != j.root && journalSkipDirs[entry.Name()] {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				skip(rel, err)
+				return nil
+			}
+			if info.Size() > maxJournalFileSize {
+				skip(rel, fmt.Errorf("larger than %d MB", maxJournalFileSize>>20))
+				return nil
+			}
+
+			file, err := j.store(path, info)
+			if err != nil {
+				skip(rel, err)
+				return nil
+			}
+			snapshot.Files[rel] = file
+			return nil
+		})
+	}
+	return snapshot
+}
+
+// diffSnapshots lists the files added, modified and deleted between two
+// snapshots, by path, leaving out those skipped by either
+func diffSnapshots(before, after journalSnapshot) []journalChange {
+	var changes []journalChange
+	for path, old := range before.Files {
+		if before.skips(path) || after.skips(path) {
+			continue
+		}
+		current, ok := after.Files[path]
+		switch {
+		case !ok:
+			changes = append(changes, journalChange{Path: path, Action: journalDeleted, Before: old.Hash, Mode: old.Mode})
+		case current.Hash != old.Hash:
+			changes = append(changes, journalChange{Path: path, Action: journalModified, Before: old.Hash, After: current.Hash, Mode: old.Mode})
+		}
+	}
+	for path, current := range after.Files {
+		if before.skips(path) || after.skips(path) {
+			continue
+		}
+		if _, ok := before.Files[path]; !ok {
+			changes = append(changes, journalChange{Path: path, Action: journalAdded, After: current.Hash})
+		}
+	}
+
+	sort.Slice(changes, func(a, b int) bool { return changes[a].Path < changes[b].Path })
+	return changes
+}
+
+// Operations returns the recorded operations, oldest first
+func (j *journal) Operations() ([]*journalOperation, error) {
+	matches, err := filepath.Glob(j.path("operations", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []*journalOperation
+	for _, match := range matches {
+		data, err := os.ReadFile(match)
+		if err != nil {
+			return nil, err
+		}
+		op := &journalOperation{}
+		if err := json.Unmarshal(data, op); err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", match, err)
+		}
+		ops = append(ops, op)
+	}
+
+	sort.Slice(ops, func(a, b int) bool { return ops[a].ID < ops[b].ID })
+	return ops, nil
+}
+
+// save writes op, numbering new operations after the last one
+func (j *journal) save(op *journalOperation) error {
+	if err := j.init(); err != nil {
+		return err
+	}
+
+	if op.ID == 0 {
+		ops, err := j.Operations()
+		if err != nil {
+			return err
+		}
+		op.ID = 1
+		if len(ops) > 0 {
+			op.ID = ops[len(ops)-1].ID + 1
+		}
+	}
+
+	data, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(j.path("operations", fmt.Sprintf("%06d.json", op.ID)), data, 0o644)
+}
+
+// runJournaled runs a mutating command on the project at root between two
+// snapshots of paths and records what it changed, even when it failed
+// halfway. Journal errors are logged and never fail the command.
+func runJournaled(root string, paths, command []string, run func() (string, error)) (string, error) {
+	defer lockJournal(root)()
+
+	j := &journal{root: root}
+	started := time.Now()
+	before := j.snapshot(paths)
+
+	output, runErr := run()
+
+	after := j.snapshot(paths)
+	changes := diffSnapshots(before, after)
+	if len(changes) == 0 {
+		return output, runErr
+	}
+
+	status := "ok"
+	if runErr != nil {
+		status = CommandFailed
+		var commandErr *CommandError
+		if errors.As(runErr, &commandErr) {
+			status = commandErr.Status
+		}
+	}
+
+	op := &journalOperation{
+		Command:    command,
+		Status:     status,
+		StartedAt:  started,
+		FinishedAt: time.Now(),
+		Changes:    changes,
+	}
+	if err := j.save(op); err != nil {
+		log.Printf("Journal: error recording %s in %s: %v", strings.Join(command, " "), root, err)
+	}
+	return output, runErr
+}
+
+// fileHash returns the sha256 of the file at path, or "" when it does not
+// exist
+func fileHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// conflicts lists the files changed by op that were edited since
+func (j *journal) conflicts(op *journalOperation) ([]string, error) {
+	var paths []string
+	for _, change := range op.Changes {
+		hash, err := fileHash(filepath.Join(j.root, filepath.FromSlash(change.Path)))
+		if err != nil {
+			return nil, err
+		}
+		if hash != change.After {
+			paths = append(paths, change.Path)
+		}
+	}
+	return paths, nil
+}
+
+// revert restores the files changed by op to their content before it and
+// removes the files it added, with the directories left empty
+func (j *journal) revert(op *journalOperation) error {
+	for _, change := range op.Changes {
+		path := filepath.Join(j.root, filepath.FromSlash(change.Path))
+
+		if change.Action == journalAdded {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			j.removeEmptyDirs(filepath.Dir(path))
+			continue
+		}
+
+		data, err := os.ReadFile(j.objectPath(change.Before))
+		if err != nil {
+			return fmt.Errorf("error reading the snapshot of %s: %w", change.Path, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, change.Mode); err != nil {
+			return err
+		}
+		if err := os.Chmod(path, change.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeEmptyDirs removes dir and its parents while they are empty, up to
+// the project root
+func (j *journal) removeEmptyDirs(dir string) {
+	for dir != j.root && strings.HasPrefix(dir, j.root) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// onlyJournalLeft reports whether the project root holds nothing but the
+// journal, as after undoing base new
+func (j *journal) onlyJournalLeft() bool {
+	entries, err := os.ReadDir(j.root)
+	if err != nil {
+		return false
+	}
+	return len(entries) == 1 && entries[0].Name() == ".base-mcp"
+}
+
+// Undo reverts the last n operations not undone yet, newest first. An
+// operation whose files were edited since is not reverted unless force is
+// set. It returns the reverted operations, and whether the project was
+// removed because its base new was undone.
+func (j *journal) Undo(n int, force bool) ([]*journalOperation, bool, error) {
+	defer lockJournal(j.root)()
+
+	ops, err := j.Operations()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var undone []*journalOperation
+	for i := len(ops) - 1; i >= 0 && len(undone) < n; i-- {
+		op := ops[i]
+		if op.UndoneAt != nil {
+			continue
+		}
+
+		if !force {
+			conflicts, err := j.conflicts(op)
+			if err != nil {
+				return undone, false, err
+			}
+			if len(conflicts) > 0 {
+				return undone, false, fmt.Errorf("operation #%d (%s) was not undone because these files changed since: %s. Pass force to overwrite them",
+					op.ID, strings.Join(op.Command, " "), strings.Join(conflicts, ", "))
+			}
+		}
+
+		if err := j.revert(op); err != nil {
+			return undone, false, fmt.Errorf("error undoing operation #%d: %w", op.ID, err)
+		}
+		undone = append(undone, op)
+
+		if len(op.Command) > 1 && op.Command[1] == "new" && j.onlyJournalLeft() {
+			return undone, true, os.RemoveAll(j.root)
+		}
+
+		now := time.Now()
+		op.UndoneAt = &now
+		if err := j.save(op); err != nil {
+			return undone, false, err
+		}
+	}
+	return undone, false, nil
+}
+
+// defaultHistoryLimit is how many operations base_history lists by default
+const defaultHistoryLimit = 10
+
+// registerJournalTools adds the tools that list and undo the operations
+// recorded in a project's journal
+func registerJournalTools(s *server.MCPServer) {
+	historyTool := mcp.NewTool("base_history",
+		mcp.WithDescription("List the generate, destroy, new and update commands run on a Base project through this server, newest first, with the files each one added, modified or deleted"),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Number of operations to return (default %d)", defaultHistoryLimit)),
+		),
+		withProjectRootArg(),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.AddTool(historyTool, handleBaseHistory)
+
+	undoTool := mcp.NewTool("base_undo",
+		mcp.WithDescription("Undo the last operations listed by base_history: restore the files they modified or deleted from the project's journal and remove the files they added. Undoing base new removes the project"),
+		mcp.WithNumber("steps",
+			mcp.Description("Number of operations to undo (default 1)"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Undo even when files were edited after the operation, losing those edits"),
+		),
+		withProjectRootArg(),
+		mcp.WithDestructiveHintAnnotation(true),
+	)
+	s.AddTool(undoTool, handleBaseUndo)
+}
+
+// operationResult describes an operation for the journal tools
+func operationResult(op *journalOperation) map[string]any {
+	result := map[string]any{
+		"id":          op.ID,
+		"command":     strings.Join(op.Command, " "),
+		"status":      op.Status,
+		"started_at":  op.StartedAt.Format(time.RFC3339),
+		"finished_at": op.FinishedAt.Format(time.RFC3339),
+		"summary":     op.Summary(),
+		"changes":     op.Changes,
+	}
+	if op.UndoneAt != nil {
+		result["undone_at"] = op.UndoneAt.Format(time.RFC3339)
+	}
+	return result
+}
+
+// writeOperation lists an operation and its changes, e.g.
+// "#2 base generate post (ok, 2026-01-02 15:04): 4 added, 1 modified"
+func writeOperation(b *strings.Builder, op *journalOperation) {
+	fmt.Fprintf(b, "#%d %s (%s, %s): %s", op.ID, strings.Join(op.Command, " "), op.Status, op.StartedAt.Local().Format("2006-01-02 15:04"), op.Summary())
+	if op.UndoneAt != nil {
+		b.WriteString(" [undone]")
+	}
+	b.WriteString("\n")
+
+	for _, change := range op.Changes {
+		sign := map[string]string{journalAdded: "+", journalModified: "~", journalDeleted: "-"}[change.Action]
+		fmt.Fprintf(b, "  %s %s\n", sign, change.Path)
+	}
+}
+
+func handleBaseHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	root, err := projectRootFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	limit := request.GetInt("limit", defaultHistoryLimit)
+	if limit < 1 {
+		limit = defaultHistoryLimit
+	}
+
+	ops, err := (&journal{root: root}).Operations()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error reading the journal of %s: %v", root, err)), nil
+	}
+
+	var b strings.Builder
+	operations := []map[string]any{}
+	for i := len(ops) - 1; i >= 0 && len(operations) < limit; i-- {
+		operations = append(operations, operationResult(ops[i]))
+		writeOperation(&b, ops[i])
+	}
+	if len(ops) == 0 {
+		fmt.Fprintf(&b, "No operations recorded for %s yet", root)
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{
+		"project_root": root,
+		"total":        len(ops),
+		"operations":   operations,
+	}, b.String()), nil
+}
+
+func handleBaseUndo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	root, err := projectRootFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	steps := request.GetInt("steps", 1)
+	if steps < 1 {
+		return mcp.NewToolResultError("steps must be at least 1"), nil
+	}
+
+	undone, removed, undoErr := (&journal{root: root}).Undo(steps, request.GetBool("force", false))
+
+	var b strings.Builder
+	operations := []map[string]any{}
+	for _, op := range undone {
+		operations = append(operations, operationResult(op))
+		b.WriteString("Undid ")
+		writeOperation(&b, op)
+	}
+	switch {
+	case removed:
+		fmt.Fprintf(&b, "Removed the project directory %s\n", root)
+	case len(undone) == 0 && undoErr == nil:
+		fmt.Fprintf(&b, "Nothing to undo in %s\n", root)
+	}
+
+	result := map[string]any{
+		"project_root":    root,
+		"undone":          operations,
+		"project_removed": removed,
+	}
+	if undoErr != nil {
+		result["error"] = undoErr.Error()
+		fmt.Fprintf(&b, "%v\n", undoErr)
+		toolResult := mcp.NewToolResultStructured(result, b.String())
+		toolResult.IsError = true
+		return toolResult, nil
+	}
+	return mcp.NewToolResultStructured(result, b.String()), nil
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes files, by slash separated path, under root. A nil
+// content removes the file.
+func writeFiles(t *testing.T, root string, files map[string]*string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if content == nil {
+			if err := os.Remove(path); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(*content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readTree returns the files under root, outside the journal
+func readTree(t *testing.T, root string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.IsDir() && entry.Name() == ".base-mcp" {
+			return filepath.SkipDir
+		}
+		if !entry.IsDir() {
+			data, _ := os.ReadFile(path)
+			rel, _ := filepath.Rel(root, path)
+			files[filepath.ToSlash(rel)] = string(data)
+		}
+		return nil
+	})
+	return files
+}
+
+func text(s string) *string { return &s }
+
+func TestJournalDiff(t *testing.T) {
+	base := map[string]*string{
+		"go.mod":                text("module myapp\n"),
+		"app/init.go":           text("package app\n"),
+		"app/post/module.go":    text("package post\n"),
+		"core/router/router.go": text("package router\n"),
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		change  map[string]*string
+		want    []string
+	}{
+		{
+			name: "nothing",
+			want: nil,
+		},
+		{
+			name: "generate",
+			change: map[string]*string{
+				"app/init.go":       text("package app\n// tag\n"),
+				"app/tag/module.go": text("package tag\n"),
+				"app/models/tag.go": text("package models\n"),
+			},
+			want: []string{"modified app/init.go", "added app/models/tag.go", "added app/tag/module.go"},
+		},
+		{
+			name: "destroy",
+			change: map[string]*string{
+				"app/init.go":        text(""),
+				"app/post/module.go": nil,
+			},
+			want: []string{"modified app/init.go", "deleted app/post/module.go"},
+		},
+		{
+			name: "runtime data is ignored",
+			change: map[string]*string{
+				"storage/uploads/a.png": text("png"),
+				"app.db":                text("sqlite"),
+				"logs/app.log":          text("started"),
+			},
+			want: nil,
+		},
+		{
+			name:    "update",
+			command: "update",
+			change: map[string]*string{
+				"core/router/router.go":     text("package router\n// v2.1.7\n"),
+				"core/router/group.go":      text("package router\n"),
+				"core.bak/router/router.go": text("package router\n"),
+				"go.sum":                    text("github.com/x v1 h1:x\n"),
+			},
+			want: []string{"added core/router/group.go", "modified core/router/router.go", "added go.sum"},
+		},
+		{
+			name: "core is left to update",
+			change: map[string]*string{
+				"core/router/router.go": text("package router\n// edited\n"),
+			},
+			want: nil,
+		},
+		{
+			name: "skipped directories are ignored",
+			change: map[string]*string{
+				"app/node_modules/x.js": text("x"),
+				"app/.git/HEAD":         text("ref"),
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, base)
+			j := &journal{root: root}
+
+			command := tt.command
+			if command == "" {
+				command = "generate"
+			}
+			paths := journaledCommands[command]
+
+			before := j.snapshot(paths)
+			writeFiles(t, root, tt.change)
+			after := j.snapshot(paths)
+
+			var got []string
+			for _, change := range diffSnapshots(before, after) {
+				got = append(got, change.Action+" "+change.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJournalDiffSkipped(t *testing.T) {
+	before := journalSnapshot{
+		Files:   map[string]journalFile{"app/a.go": {Hash: "1"}},
+		Skipped: map[string]bool{"app/big": true},
+	}
+	after := journalSnapshot{
+		Files: map[string]journalFile{
+			"app/a.go":        {Hash: "1"},
+			"app/big/x.go":    {Hash: "2"},
+			"app/huge.bin":    {Hash: "3"},
+			"app/models/b.go": {Hash: "4"},
+		},
+		Skipped: map[string]bool{"app/huge.bin": true},
+	}
+
+	changes := diffSnapshots(before, after)
+	if len(changes) != 1 || changes[0].Path != "app/models/b.go" {
+		t.Errorf("changes = %+v, want only app/models/b.go added", changes)
+	}
+}
+
+func TestJournalUndo(t *testing.T) {
+	original := map[string]*string{
+		"go.mod":             text("module myapp\n"),
+		"app/init.go":        text("package app\n"),
+		"app/post/module.go": text("package post\n"),
+	}
+	generate := map[string]*string{
+		"app/init.go":       text("package app\n// tag\n"),
+		"app/tag/module.go": text("package tag\n"),
+	}
+	destroy := map[string]*string{
+		"app/init.go":        text("package app\n// tag\n// post removed\n"),
+		"app/post/module.go": nil,
+	}
+
+	tests := []struct {
+		name    string
+		steps   int
+		force   bool
+		edit    map[string]*string
+		undone  int
+		wantErr string
+		want    map[string]string
+	}{
+		{
+			name:   "last operation",
+			steps:  1,
+			undone: 1,
+			want: map[string]string{
+				"go.mod":             "module myapp\n",
+				"app/init.go":        "package app\n// tag\n",
+				"app/post/module.go": "package post\n",
+				"app/tag/module.go":  "package tag\n",
+			},
+		},
+		{
+			name:   "both operations",
+			steps:  5,
+			undone: 2,
+			want: map[string]string{
+				"go.mod":             "module myapp\n",
+				"app/init.go":        "package app\n",
+				"app/post/module.go": "package post\n",
+			},
+		},
+		{
+			name:    "file edited since",
+			steps:   2,
+			edit:    map[string]*string{"app/tag/module.go": text("package tag // edited\n")},
+			undone:  1,
+			wantErr: "app/tag/module.go",
+			want: map[string]string{
+				"go.mod":             "module myapp\n",
+				"app/init.go":        "package app\n// tag\n",
+				"app/post/module.go": "package post\n",
+				"app/tag/module.go":  "package tag // edited\n",
+			},
+		},
+		{
+			name:    "file recreated since",
+			steps:   1,
+			edit:    map[string]*string{"app/post/module.go": text("package post // new\n")},
+			wantErr: "app/post/module.go",
+			want: map[string]string{
+				"go.mod":             "module myapp\n",
+				"app/init.go":        "package app\n// tag\n// post removed\n",
+				"app/post/module.go": "package post // new\n",
+				"app/tag/module.go":  "package tag\n",
+			},
+		},
+		{
+			name:   "forced",
+			steps:  2,
+			force:  true,
+			edit:   map[string]*string{"app/tag/module.go": text("package tag // edited\n")},
+			undone: 2,
+			want: map[string]string{
+				"go.mod":             "module myapp\n",
+				"app/init.go":        "package app\n",
+				"app/post/module.go": "package post\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, original)
+
+			for _, step := range []map[string]*string{generate, destroy} {
+				runJournaled(root, journalSourcePaths, []string{"base", "test"}, func() (string, error) {
+					writeFiles(t, root, step)
+					return "", nil
+				})
+			}
+			writeFiles(t, root, tt.edit)
+
+			undone, removed, err := (&journal{root: root}).Undo(tt.steps, tt.force)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Undo: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("Undo error = %v, want one naming %s", err, tt.wantErr)
+			}
+			if len(undone) != tt.undone || removed {
+				t.Errorf("undid %d operations (project removed: %v), want %d", len(undone), removed, tt.undone)
+			}
+			if got := readTree(t, root); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tree after undo:\n%v\nwant:\n%v", got, tt.want)
+			}
+			if _, err := os.Stat(filepath.Join(root, "app", "tag")); err == nil && tt.want["app/tag/module.go"] == "" {
+				t.Errorf("the emptied app/tag directory was not removed")
+			}
+
+			ops, _ := (&journal{root: root}).Operations()
+			var marked []int
+			for _, op := range ops {
+				if op.UndoneAt != nil {
+					marked = append(marked, op.ID)
+				}
+			}
+			sort.Ints(marked)
+			if len(marked) != tt.undone {
+				t.Errorf("operations marked undone: %v, want %d", marked, tt.undone)
+			}
+		})
+	}
+}
+
+func TestJournalUndoNew(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "shop")
+	runJournaled(root, journaledCommands["new"], []string{"base", "new", "shop"}, func() (string, error) {
+		writeFiles(t, root, map[string]*string{
+			"go.mod":      text("module shop\n"),
+			"app/init.go": text("package app\n"),
+			"README.md":   text("# shop\n"),
+		})
+		return "", nil
+	})
+
+	_, removed, err := (&journal{root: root}).Undo(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, statErr := os.Stat(root); !removed || !os.IsNotExist(statErr) {
+		t.Errorf("undoing base new left the project directory (removed: %v)", removed)
+	}
+}
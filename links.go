@@ -0,0 +1,512 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var (
+	markdownLinkPattern = regexp.MustCompile(`\[[^\]]*\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	inlineCodePattern   = regexp.MustCompile("`[^`]*`")
+	packageRefPattern   = regexp.MustCompile(`\b([a-z]+)\.([A-Z]\w*)`)
+	importPathPattern   = regexp.MustCompile(`"base/core/([a-z]+)"`)
+	urlSchemePattern    = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+// packageDocAliases maps core packages to the doc that covers them when the
+// package name differs from the doc file name
+var packageDocAliases = map[string]string{
+	"authorization": "auth",
+	"helper":        "base-helpers",
+	"types":         "base-helpers",
+}
+
+// docLink is an edge of the link graph: a markdown link, a frontmatter link:
+// entry or a mention of a core package, from a section of one file to another
+// file or section
+type docLink struct {
+	Kind   string   `json:"kind"`
+	File   string   `json:"file"`
+	Path   []string `json:"path,omitempty"`
+	Line   int      `json:"line"`
+	Target string   `json:"target"`
+	ToFile string   `json:"to_file,omitempty"`
+	ToPath []string `json:"to_path,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// linkGraph holds every resolved link of a doc set and the internal links
+// that do not resolve
+type linkGraph struct {
+	Links  []docLink
+	Broken []docLink
+}
+
+// headingSlug builds the anchor of a heading the way the docs site does,
+// e.g. "Context Helpers &amp; Retrieving" to "context-helpers-retrieving"
+func headingSlug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range normalizeHeading(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		case r == ' ' || r == '-':
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// corePackages maps core package names to the doc covering them, e.g.
+// "router" to "docs/router.md"
+func corePackages(set *docSet) map[string]string {
+	packages := make(map[string]string)
+	for _, file := range set.Files() {
+		name := strings.TrimSuffix(path.Base(file), ".md")
+		if strings.HasPrefix(file, "docs/") && name != "index" {
+			packages[strings.ReplaceAll(name, "-", "")] = file
+		}
+	}
+	for pkg, name := range packageDocAliases {
+		if file, ok := set.Resolve(name); ok {
+			packages[pkg] = file
+		}
+	}
+	return packages
+}
+
+// buildLinkGraph parses every file of the set into the link graph
+func buildLinkGraph(set *docSet) *linkGraph {
+	graph := &linkGraph{}
+
+	files := set.Files()
+	sections := make(map[string][]docSection, len(files))
+	for _, file := range files {
+		if content, err := set.ReadFile(file); err == nil {
+			sections[file] = splitSections(file, string(content))
+		}
+	}
+	packages := corePackages(set)
+
+	add := func(link docLink) {
+		if link.Error != "" {
+			graph.Broken = append(graph.Broken, link)
+			return
+		}
+		graph.Links = append(graph.Links, link)
+	}
+
+	for _, file := range files {
+		content, err := set.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		lines := splitLines(string(content))
+
+		if doc, _ := parseDocument(file, string(content)); doc != nil {
+			for _, target := range frontmatterLinks(doc.Meta) {
+				if link, ok := resolveDocLink(set, sections, file, target); ok {
+					link.Kind = "frontmatter"
+					link.Line = frontmatterLinkLine(lines, target)
+					add(link)
+				}
+			}
+		}
+
+		mentioned := make(map[string]bool)
+		var stack []markdownHeading
+		fence := ""
+
+		for i := frontmatterLineCount(lines); i < len(lines); i++ {
+			line := lines[i]
+			inCode := fence != ""
+
+			if marker := fenceMarker(line); marker != "" {
+				if fence == "" {
+					fence = marker
+				} else if isClosingFence(line, fence) {
+					fence = ""
+				}
+				continue
+			}
+
+			if !inCode {
+				if level, title, ok := parseHeading(line); ok && title != "" {
+					for len(stack) > 0 && stack[len(stack)-1].Level >= level {
+						stack = stack[:len(stack)-1]
+					}
+					stack = append(stack, markdownHeading{Line: i + 1, Level: level, Title: title})
+				}
+			}
+			headingPath := make([]string, len(stack))
+			for j, h := range stack {
+				headingPath[j] = h.Title
+			}
+
+			if !inCode {
+				for _, match := range markdownLinkPattern.FindAllStringSubmatch(inlineCodePattern.ReplaceAllString(line, ""), -1) {
+					if link, ok := resolveDocLink(set, sections, file, match[1]); ok {
+						link.Kind = "link"
+						link.Path = headingPath
+						link.Line = i + 1
+						add(link)
+					}
+				}
+			}
+
+			var refs [][2]string
+			for _, match := range packageRefPattern.FindAllStringSubmatch(line, -1) {
+				refs = append(refs, [2]string{match[1], match[2]})
+			}
+			for _, match := range importPathPattern.FindAllStringSubmatch(line, -1) {
+				refs = append(refs, [2]string{match[1], ""})
+			}
+
+			for _, ref := range refs {
+				target, ok := packages[ref[0]]
+				if !ok || target == file {
+					continue
+				}
+
+				key := strings.Join(headingPath, " > ") + "|" + ref[0] + "." + ref[1]
+				if mentioned[key] {
+					continue
+				}
+				mentioned[key] = true
+
+				mention := ref[0]
+				if ref[1] != "" {
+					mention += "." + ref[1]
+				}
+				add(docLink{
+					Kind:   "mention",
+					File:   file,
+					Path:   headingPath,
+					Line:   i + 1,
+					Target: mention,
+					ToFile: target,
+					ToPath: symbolSection(sections[target], ref[1]),
+				})
+			}
+		}
+	}
+
+	return graph
+}
+
+// frontmatterLinks returns the link: entries of hero actions and features
+func frontmatterLinks(meta docMeta) []string {
+	var links []string
+	if meta.Hero != nil {
+		for _, action := range meta.Hero.Actions {
+			if action.Link != "" {
+				links = append(links, action.Link)
+			}
+		}
+	}
+	for _, feature := range meta.Features {
+		if feature.Link != "" {
+			links = append(links, feature.Link)
+		}
+	}
+	return links
+}
+
+// frontmatterLinkLine returns the 1-based line of a link: entry, or 1
+func frontmatterLinkLine(lines []string, target string) int {
+	for i, line := range lines[:frontmatterLineCount(lines)] {
+		if strings.Contains(line, "link:") && strings.Contains(line, target) {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// resolveDocLink resolves a link target found in file. It reports false for
+// external links; internal links that do not resolve carry an Error.
+func resolveDocLink(set *docSet, sections map[string][]docSection, file, target string) (docLink, bool) {
+	link := docLink{File: file, Target: target}
+	if target == "" || urlSchemePattern.MatchString(target) || strings.HasPrefix(target, "//") {
+		return link, false
+	}
+
+	p, anchor, _ := strings.Cut(target, "#")
+	p, _, _ = strings.Cut(p, "?")
+
+	if p == "" {
+		link.ToFile = file
+	} else {
+		if strings.HasPrefix(p, "/") {
+			p = strings.TrimPrefix(p, "/")
+		} else {
+			p = path.Join(path.Dir(file), p)
+		}
+		p = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(p, "/"), ".html"), ".md")
+
+		resolved, ok := set.Resolve(p)
+		if !ok {
+			resolved, ok = set.Resolve(p + "/index")
+		}
+		if !ok {
+			link.Error = "missing file"
+			return link, true
+		}
+		link.ToFile = resolved
+	}
+
+	if anchor != "" {
+		link.ToPath = anchorSection(sections[link.ToFile], anchor)
+		if link.ToPath == nil {
+			link.Error = "missing anchor"
+		}
+	}
+
+	return link, true
+}
+
+// anchorSection returns the heading path of the section with the given anchor
+func anchorSection(sections []docSection, anchor string) []string {
+	anchor = strings.ToLower(anchor)
+	for _, section := range sections {
+		if section.Title != "" && headingSlug(section.Title) == anchor {
+			return section.Path
+		}
+	}
+	return nil
+}
+
+// symbolSection returns the heading path of the section that best documents
+// symbol: one titled after it, or else the first one using it
+func symbolSection(sections []docSection, symbol string) []string {
+	if symbol == "" {
+		return nil
+	}
+	for _, section := range sections {
+		if strings.Contains(section.Title, symbol) {
+			return section.Path
+		}
+	}
+	for _, section := range sections {
+		if section.Title != "" && strings.Contains(section.Text, symbol) {
+			return section.Path
+		}
+	}
+	return nil
+}
+
+// hasPathPrefix reports whether heading path p lies within prefix
+func hasPathPrefix(p, prefix []string) bool {
+	if len(p) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if p[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// relatedDoc is a file or section related to the one asked about
+type relatedDoc struct {
+	File      string   `json:"file"`
+	Path      []string `json:"path,omitempty"`
+	URI       string   `json:"uri"`
+	Score     float64  `json:"score"`
+	Relations []string `json:"relations"`
+}
+
+// Related ranks the files and sections connected to file (narrowed to the
+// section at scope when given): direct links and mentions in both directions,
+// what those targets link to in turn, and sections on similar topics. Each
+// distinct relation counts once, so a doc mentioned on every line does not
+// drown out the rest.
+func (g *linkGraph) Related(set *docSet, file string, scope []string, limit int) []relatedDoc {
+	weights := map[string]float64{"link": 3, "frontmatter": 3, "mention": 1}
+	byKey := make(map[string]*relatedDoc)
+
+	add := func(toFile string, toPath []string, score float64, relation string) {
+		if toFile == file && (len(scope) == 0 || hasPathPrefix(toPath, scope)) {
+			return
+		}
+		key := toFile + " > " + strings.Join(toPath, " > ")
+		r, ok := byKey[key]
+		if !ok {
+			r = &relatedDoc{File: toFile, Path: toPath}
+			byKey[key] = r
+		}
+		if containsString(r.Relations, relation) {
+			return
+		}
+		r.Score += score
+		r.Relations = append(r.Relations, relation)
+	}
+
+	// Mentions of several symbols of the same target are one relation
+	type mentioned struct {
+		link    docLink
+		symbols []string
+	}
+	var mentions []*mentioned
+	mentionsByKey := make(map[string]*mentioned)
+
+	var hops []docLink
+	for _, link := range g.Links {
+		if link.File == file && hasPathPrefix(link.Path, scope) {
+			if link.Kind == "mention" {
+				key := link.ToFile + " > " + strings.Join(link.ToPath, " > ")
+				m, ok := mentionsByKey[key]
+				if !ok {
+					m = &mentioned{link: link}
+					mentionsByKey[key] = m
+					mentions = append(mentions, m)
+				}
+				if !containsString(m.symbols, link.Target) {
+					m.symbols = append(m.symbols, link.Target)
+				}
+			} else {
+				add(link.ToFile, link.ToPath, weights[link.Kind], "links to")
+			}
+			hops = append(hops, link)
+		}
+		if link.ToFile == file && link.File != file && (len(scope) == 0 || hasPathPrefix(link.ToPath, scope)) {
+			verb := "linked from"
+			if link.Kind == "mention" {
+				verb = "mentioned by"
+			}
+			add(link.File, link.Path, weights[link.Kind]*2/3, verb)
+		}
+	}
+
+	for _, m := range mentions {
+		add(m.link.ToFile, m.link.ToPath, weights["mention"], "mentions "+strings.Join(m.symbols, ", "))
+	}
+
+	for _, hop := range hops {
+		for _, link := range g.Links {
+			if link.File != hop.ToFile || link.ToFile == hop.ToFile || !hasPathPrefix(link.Path, hop.ToPath) {
+				continue
+			}
+			add(link.ToFile, link.ToPath, weights[link.Kind]*weights[hop.Kind]/10, "via "+hop.ToFile)
+		}
+	}
+
+	if query := strings.Join(scope, " "); query != "" {
+		hits := set.Index().Search(query, limit*2)
+		if len(hits) > 0 && hits[0].Score == 0 {
+			hits = nil
+		}
+		for _, hit := range hits {
+			if hit.File != file {
+				add(hit.File, splitHeadingPath(hit.Heading), 2*hit.Score/hits[0].Score, "similar topic")
+			}
+		}
+	}
+
+	related := make([]relatedDoc, 0, len(byKey))
+	for _, r := range byKey {
+		r.Score = float64(int(r.Score*100+0.5)) / 100
+		r.URI = docSectionURI(set, r.File, r.Path)
+		related = append(related, *r)
+	}
+
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].File+strings.Join(related[i].Path, ">") < related[j].File+strings.Join(related[j].Path, ">")
+	})
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related
+}
+
+func handleBaseRelatedDocs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	set, err := docSetFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	limit := request.GetInt("limit", 10)
+	switch {
+	case limit < 1:
+		limit = 10
+	case limit > 50:
+		limit = 50
+	}
+
+	graph := set.Links()
+	segments := splitHeadingPath(request.GetString("path", ""))
+
+	if len(segments) == 0 {
+		var text strings.Builder
+		fmt.Fprintf(&text, "%d internal links, %d broken\n", len(graph.Links), len(graph.Broken))
+		for _, link := range graph.Broken {
+			fmt.Fprintf(&text, "- %s:%d %s (%s)\n", link.File, link.Line, link.Target, link.Error)
+		}
+		return mcp.NewToolResultStructured(map[string]any{
+			"version":      set.Version,
+			"links":        len(graph.Links),
+			"broken_links": graph.Broken,
+		}, strings.TrimSpace(text.String())), nil
+	}
+
+	outline, err := set.Outline(segments[0])
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var scope []string
+	if len(segments) > 1 {
+		trail := outline.Find(segments[1:])
+		if trail == nil {
+			return mcp.NewToolResultErrorf("Section %q not found in %s", strings.Join(segments[1:], " > "), outline.File), nil
+		}
+		scope = headingTitles(trail)
+	}
+
+	related := graph.Related(set, outline.File, scope, limit)
+
+	var broken []docLink
+	for _, link := range graph.Broken {
+		if link.File == outline.File && hasPathPrefix(link.Path, scope) {
+			broken = append(broken, link)
+		}
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Related to %s\n\n", strings.Join(append([]string{outline.File}, scope...), " > "))
+	if len(related) == 0 {
+		text.WriteString("No related documentation found\n")
+	}
+	for i, r := range related {
+		fmt.Fprintf(&text, "%d. %s (score %.2f) — %s\n", i+1, strings.Join(append([]string{r.File}, r.Path...), " > "), r.Score, strings.Join(r.Relations, ", "))
+	}
+	if len(broken) > 0 {
+		text.WriteString("\nBroken links:\n")
+		for _, link := range broken {
+			fmt.Fprintf(&text, "- line %d: %s (%s)\n", link.Line, link.Target, link.Error)
+		}
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{
+		"file":         outline.File,
+		"path":         scope,
+		"version":      set.Version,
+		"related":      related,
+		"broken_links": broken,
+	}, strings.TrimSpace(text.String())), nil
+}
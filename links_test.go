@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestRelatedMentionsCountOnce(t *testing.T) {
+	set := &docSet{Version: "v2", Locale: defaultLocale, fsys: fstest.MapFS{
+		"docs/router.md": {Data: []byte("---\ntitle: Router\n---\n# Router\nRoutes requests.\n")},
+		"docs/events.md": {Data: []byte("---\ntitle: Events\n---\n# Events\nEmit events.\n")},
+		"docs/auth.md": {Data: []byte("---\ntitle: Auth\n---\n# Auth\n" +
+			"Use router.Group for protected routes.\n" +
+			"Add router.Use middleware.\n" +
+			"Register with router.Handle.\n" +
+			"See [events](./events.md).\n")},
+	}}
+
+	related := set.Links().Related(set, "docs/auth.md", nil, 10)
+	scores := make(map[string]relatedDoc)
+	for _, r := range related {
+		scores[r.File] = r
+	}
+
+	router, ok := scores["docs/router.md"]
+	if !ok {
+		t.Fatalf("Related() = %+v, want docs/router.md", related)
+	}
+	if router.Score != 1 {
+		t.Errorf("router score = %v, want 1 for one mention relation", router.Score)
+	}
+	if want := "mentions router.Group, router.Use, router.Handle"; len(router.Relations) != 1 || router.Relations[0] != want {
+		t.Errorf("router relations = %q, want [%q]", router.Relations, want)
+	}
+
+	if events := scores["docs/events.md"]; events.Score <= router.Score {
+		t.Errorf("events score = %v, want the link to outrank the mentions (%v)", events.Score, router.Score)
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// htmlEntityPattern matches HTML entities that leaked into markdown source
+var htmlEntityPattern = regexp.MustCompile(`&(?:[a-zA-Z]+|#[0-9]+|#x[0-9a-fA-F]+);`)
+
+// minFlattenedTableCells is the shortest run of one-cell lines reported as a
+// flattened table
+const minFlattenedTableCells = 8
+
+// lintIssue is one problem found in a documentation file
+type lintIssue struct {
+	Rule    string `json:"rule"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// lintReport is the result of linting one doc set
+type lintReport struct {
+	Version string         `json:"version"`
+	Files   int            `json:"files"`
+	Counts  map[string]int `json:"counts"`
+	Issues  []lintIssue    `json:"issues"`
+}
+
+// runLintDocs implements `base-mcp lint-docs`. It prints a JSON report for
+// every doc set and returns 1 when any issue was found, 2 when the docs
+// could not be loaded.
+func runLintDocs(args []string) int {
+	flags := flag.NewFlagSet("lint-docs", flag.ContinueOnError)
+	dir := flags.String("path", os.Getenv("DOCS_PATH"), "docs directory to lint instead of the embedded docs (defaults to DOCS_PATH)")
+	version := flags.String("version", "", "only lint this version, e.g. v2")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	sets, err := lintDocSets(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lint-docs: %v\n", err)
+		return 2
+	}
+
+	var reports []lintReport
+	total := 0
+	for _, v := range sortVersions(sets) {
+		if *version != "" && v != normalizeDocVersion(*version) {
+			continue
+		}
+		report := lintDocSet(sets[v])
+		total += len(report.Issues)
+		reports = append(reports, report)
+	}
+	if len(reports) == 0 {
+		fmt.Fprintf(os.Stderr, "lint-docs: version %q not found\n", *version)
+		return 2
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	out.Encode(map[string]any{
+		"issues":  total,
+		"reports": reports,
+	})
+
+	if total > 0 {
+		return 1
+	}
+	return 0
+}
+
+// lintDocSets loads the doc sets to lint: the embedded ones, or the sets
+// dir covers laid over the embedded docs as when it is served from
+// DOCS_PATH, so that its links to embedded files resolve. A directory with
+// version directories covers those versions, any other the newest one.
+func lintDocSets(dir string) (map[string]*docSet, error) {
+	if dir == "" {
+		return loadDocSets(docsFS, docsRoot, "")
+	}
+
+	sets, err := loadDocSets(docsFS, docsRoot, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	covered := make(map[string]*docSet)
+	for _, entry := range entries {
+		if entry.IsDir() && versionDirPattern.MatchString(entry.Name()) {
+			covered[entry.Name()] = sets[entry.Name()]
+		}
+	}
+	if len(covered) == 0 {
+		versions := sortVersions(sets)
+		version := versions[len(versions)-1]
+		covered[version] = sets[version]
+	}
+	return covered, nil
+}
+
+// lintDocSet checks every file of the set
+func lintDocSet(set *docSet) lintReport {
+	report := lintReport{Version: set.Version, Counts: make(map[string]int)}
+
+	add := func(issue lintIssue) {
+		report.Issues = append(report.Issues, issue)
+		report.Counts[issue.Rule]++
+	}
+
+	files := set.Files()
+	report.Files = len(files)
+
+	for _, file := range files {
+		content, err := set.ReadFile(file)
+		if err != nil {
+			add(lintIssue{Rule: "unreadable", File: file, Line: 1, Message: err.Error()})
+			continue
+		}
+		for _, issue := range lintDocument(file, string(content)) {
+			add(issue)
+		}
+	}
+
+	for _, link := range set.Links().Broken {
+		add(lintIssue{Rule: "dangling-link", File: link.File, Line: link.Line, Message: fmt.Sprintf("link to %s: %s", link.Target, link.Error)})
+	}
+
+	if report.Issues == nil {
+		report.Issues = []lintIssue{}
+	}
+	return report
+}
+
+// lintDocument checks a single markdown file
+func lintDocument(file, content string) []lintIssue {
+	var issues []lintIssue
+	lines := splitLines(content)
+
+	doc, err := parseDocument(file, content)
+	switch {
+	case err != nil:
+		issues = append(issues, lintIssue{Rule: "frontmatter", File: file, Line: 1, Message: "malformed frontmatter: " + err.Error()})
+	case !doc.HasFrontmatter:
+		issues = append(issues, lintIssue{Rule: "frontmatter", File: file, Line: 1, Message: "missing frontmatter"})
+	case doc.Meta.Title == "" && doc.Meta.Layout != "home":
+		issues = append(issues, lintIssue{Rule: "frontmatter", File: file, Line: 1, Message: "frontmatter has no title"})
+	}
+
+	for _, heading := range scanHeadings(lines) {
+		if heading.Title == "" {
+			issues = append(issues, lintIssue{Rule: "empty-heading", File: file, Line: heading.Line, Message: fmt.Sprintf("empty level %d heading", heading.Level)})
+		}
+	}
+
+	for i, line := range lines {
+		for _, entity := range htmlEntityPattern.FindAllString(line, -1) {
+			issues = append(issues, lintIssue{Rule: "html-entity", File: file, Line: i + 1, Message: fmt.Sprintf("HTML entity %s in markdown source", entity)})
+		}
+	}
+
+	issues = append(issues, lintDuplicateHeadings(file, parseOutline(file, content).Headings)...)
+	issues = append(issues, lintFlattenedTables(file, lines)...)
+
+	return issues
+}
+
+// lintDuplicateHeadings reports sibling headings with the same title, which
+// produce ambiguous heading paths and anchors
+func lintDuplicateHeadings(file string, headings []*docHeading) []lintIssue {
+	var issues []lintIssue
+	seen := make(map[string]int)
+
+	for _, h := range headings {
+		key := normalizeHeading(h.Title)
+		if first, ok := seen[key]; ok {
+			issues = append(issues, lintIssue{Rule: "duplicate-heading", File: file, Line: h.StartLine, Message: fmt.Sprintf("heading %q duplicates line %d", h.Title, first)})
+		} else {
+			seen[key] = h.StartLine
+		}
+		issues = append(issues, lintDuplicateHeadings(file, h.Children)...)
+	}
+
+	return issues
+}
+
+// lintFlattenedTables reports runs of short single-cell lines outside code
+// blocks, which is what a markdown table looks like after its pipes were
+// stripped (see the field type tables in cli.md)
+func lintFlattenedTables(file string, lines []string) []lintIssue {
+	var issues []lintIssue
+	start, count := 0, 0
+	fence := ""
+
+	flush := func() {
+		if count >= minFlattenedTableCells {
+			issues = append(issues, lintIssue{Rule: "flattened-table", File: file, Line: start + 1, Message: fmt.Sprintf("%d consecutive one-cell lines look like a table without pipes", count)})
+		}
+		count = 0
+	}
+
+	for i := frontmatterLineCount(lines); i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if marker := fenceMarker(lines[i]); marker != "" {
+			flush()
+			if fence == "" {
+				fence = marker
+			} else if isClosingFence(lines[i], fence) {
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		if !isTableCell(line) {
+			flush()
+			continue
+		}
+		if count == 0 {
+			start = i
+		}
+		count++
+	}
+	flush()
+
+	return issues
+}
+
+// isTableCell reports whether line could be a single cell of a flattened
+// table: short, not a heading, list item, table row, or sentence
+func isTableCell(line string) bool {
+	if line == "" || len(line) > 40 || len(strings.Fields(line)) > 5 {
+		return false
+	}
+	if _, _, ok := parseHeading(line); ok {
+		return false
+	}
+	for _, prefix := range []string{"- ", "* ", "+ ", "|", ">", "<", "!["} {
+		if strings.HasPrefix(line, prefix) {
+			return false
+		}
+	}
+	return !strings.HasSuffix(line, ".") && !strings.HasSuffix(line, ":")
+}
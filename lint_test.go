@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLintDocSetsOverlay(t *testing.T) {
+	const doc = "---\ntitle: Guide\n---\n# Guide\nSee the [router](./router.md) and a [missing page](./missing.md).\n"
+
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"unversioned", "docs/guide.md"},
+		{"versioned", "v2/docs/guide.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, filepath.FromSlash(tt.file))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			sets, err := lintDocSets(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			set, ok := sets["v2"]
+			if len(sets) != 1 || !ok {
+				t.Fatalf("linted versions %v, want only v2", sortVersions(sets))
+			}
+			if set.Locale != defaultLocale {
+				t.Errorf("Locale = %q, want %q", set.Locale, defaultLocale)
+			}
+
+			var broken []string
+			for _, issue := range lintDocSet(set).Issues {
+				if issue.File == "docs/guide.md" {
+					broken = append(broken, issue.Message)
+				}
+			}
+			if len(broken) != 1 || broken[0] != "link to ./missing.md: missing file" {
+				t.Errorf("issues of docs/guide.md = %q, want only the missing page", broken)
+			}
+		})
+	}
+}
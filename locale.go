@@ -0,0 +1,155 @@
+package main
+
+import (
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultLocale is the language of the doc set version directories; every
+// translation falls back to it file by file
+const defaultLocale = "en"
+
+// localeDirPattern matches the translation directories inside a version
+// directory, e.g. md/v2/fr or md/v2/pt-br
+var localeDirPattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// normalizeLocale maps "pt_BR" or " PT-br " to the directory name "pt-br"
+func normalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
+
+// localeLanguages are the languages a translation directory may be named
+// after, optionally with a region such as pt-br. Other short directories
+// such as api/ or cli/ are docs, and codes that double as common directory
+// names (io, os, ts) are left out.
+var localeLanguages = map[string]bool{
+	"ar": true, "bg": true, "bn": true, "ca": true, "cs": true, "da": true,
+	"de": true, "el": true, "en": true, "es": true, "et": true, "fa": true,
+	"fi": true, "fil": true, "fr": true, "he": true, "hi": true, "hr": true,
+	"hu": true, "id": true, "it": true, "ja": true, "ko": true, "lt": true,
+	"lv": true, "ms": true, "nb": true, "nl": true, "no": true, "pl": true,
+	"pt": true, "ro": true, "ru": true, "sk": true, "sl": true, "sr": true,
+	"sv": true, "sw": true, "th": true, "tr": true, "uk": true, "ur": true,
+	"vi": true, "zh": true,
+}
+
+// isLocaleDir reports whether p is a translation directory at the root of a
+// doc set
+func isLocaleDir(p string) bool {
+	if strings.Contains(p, "/") || !localeDirPattern.MatchString(p) {
+		return false
+	}
+	language, _, _ := strings.Cut(p, "-")
+	return localeLanguages[language]
+}
+
+// loadTranslations creates a doc set for every translation directory of s.
+// A translated set overlays the translation on the English files, so files
+// that are not translated yet are served in English.
+func (s *docSet) loadTranslations() error {
+	entries, err := fs.ReadDir(s.fsys, ".")
+	if err != nil {
+		return err
+	}
+
+	s.translations = make(map[string]*docSet)
+	for _, entry := range entries {
+		if !entry.IsDir() || !isLocaleDir(entry.Name()) || entry.Name() == defaultLocale {
+			continue
+		}
+		sub, err := fs.Sub(s.fsys, entry.Name())
+		if err != nil {
+			return err
+		}
+		s.translations[entry.Name()] = &docSet{
+			Version:    s.Version,
+			Locale:     entry.Name(),
+			fsys:       newOverlayFS(sub, s.fsys),
+			translated: sub,
+		}
+	}
+	return nil
+}
+
+// Locales returns the locales the set is available in, English first
+func (s *docSet) Locales() []string {
+	locales := make([]string, 0, len(s.translations))
+	for locale := range s.translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return append([]string{defaultLocale}, locales...)
+}
+
+// Localized returns the translation of the set for locale, trying the
+// primary language of a regional locale such as "fr-ca" as well. It returns
+// the set itself when locale is empty or not translated.
+func (s *docSet) Localized(locale string) *docSet {
+	locale = normalizeLocale(locale)
+	if translation, ok := s.translations[locale]; ok {
+		return translation
+	}
+	if primary, _, ok := strings.Cut(locale, "-"); ok {
+		if translation, ok := s.translations[primary]; ok {
+			return translation
+		}
+	}
+	return s
+}
+
+// FileLocale returns the locale a file of the set is served in, which is
+// English for files the translation does not have
+func (s *docSet) FileLocale(file string) string {
+	if s.translated != nil {
+		if _, err := fs.Stat(s.translated, file); err == nil {
+			return s.Locale
+		}
+	}
+	return defaultLocale
+}
+
+// negotiateLocale picks the translation of set that best matches an HTTP
+// Accept-Language header, e.g. "fr-CH, fr;q=0.9, en;q=0.8"
+func negotiateLocale(set *docSet, acceptLanguage string) *docSet {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var ranges []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		locale, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if locale = normalizeLocale(locale); locale != "" && locale != "*" && q > 0 {
+			ranges = append(ranges, weighted{locale, q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	for _, r := range ranges {
+		if r.locale == defaultLocale || strings.HasPrefix(r.locale, defaultLocale+"-") {
+			return set
+		}
+		if translation := set.Localized(r.locale); translation != set {
+			return translation
+		}
+	}
+	return set
+}
+
+// withLocaleArg declares the optional locale argument shared by doc tools
+func withLocaleArg() mcp.ToolOption {
+	return mcp.WithString("locale",
+		mcp.Description("Language of the docs, e.g. fr or pt-br. Files without a translation are returned in English (default en)"),
+	)
+}
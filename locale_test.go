@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestIsLocaleDir(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{"fr", true},
+		{"pt-br", true},
+		{"zh-hans", true},
+		{"fil", true},
+		{"api", false},
+		{"cli", false},
+		{"orm", false},
+		{"ts", false},
+		{"docs", false},
+		{"fr/docs", false},
+		{"pt_br", false},
+	}
+
+	for _, tt := range tests {
+		if got := isLocaleDir(tt.dir); got != tt.want {
+			t.Errorf("isLocaleDir(%q) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+}
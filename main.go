@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -17,19 +18,199 @@ import (
 var docsFS embed.FS
 
 func main() {
-	// Create simple MCP server
-	mcpServer := server.NewMCPServer("Base Framework", "1.0.0")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "lint-docs":
+			os.Exit(runLintDocs(os.Args[2:]))
+		case "verify-examples":
+			os.Exit(runVerifyExamples(os.Args[2:]))
+		}
+	}
+
+	// Load the embedded documentation sets, overlaid with DOCS_PATH if set
+	if err := initDocSets(); err != nil {
+		log.Fatalf("Documentation error: %v", err)
+	}
+
+	// Create simple MCP server, cancelling tool calls the client cancels
+	cancellations := newToolCancellations()
+	options := append(cancellations.serverOptions(&server.Hooks{}),
+		server.WithResourceCapabilities(false, true),
+		server.WithCompletions(),
+		server.WithLogging(),
+		server.WithPromptCompletionProvider(docCompletionProvider{}),
+		server.WithResourceCompletionProvider(docCompletionProvider{}),
+	)
+	mcpServer := server.NewMCPServer("Base Framework", "1.0.0", options...)
+	cancellations.register(mcpServer)
 
 	// Add Base Framework tools
 	infoTool := mcp.NewTool("base_info", mcp.WithDescription("Get Base Framework information"))
 	mcpServer.AddTool(infoTool, handleBaseInfo)
 
-	cliTool := mcp.NewTool("base_cli", mcp.WithDescription("Get Base Framework CLI commands and usage"))
+	cliTool := mcp.NewTool("base_cli", mcp.WithDescription("Get Base Framework CLI commands and usage"), withVersionArg(), withLocaleArg())
 	mcpServer.AddTool(cliTool, handleBaseCLI)
 
-	docsTool := mcp.NewTool("base_docs", mcp.WithDescription("Get Base Framework documentation and features"))
+	docsTool := mcp.NewTool("base_docs",
+		mcp.WithDescription("Get Base Framework documentation and features. Covers every documentation file by default; large results are paged with a cursor"),
+		mcp.WithArray("topics",
+			mcp.Description("Documentation files to include, e.g. [\"router\", \"auth\", \"scheduler\"]. Defaults to all files"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("max_tokens",
+			mcp.Description(fmt.Sprintf("Approximate size budget per page in tokens (default %d)", defaultDocsMaxTokens)),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("Cursor returned by a previous call to fetch the next page"),
+		),
+		withVersionArg(),
+		withLocaleArg(),
+	)
 	mcpServer.AddTool(docsTool, handleBaseDocs)
 
+	docFileTool := mcp.NewTool("base_doc_file",
+		mcp.WithDescription("Get a single Base Framework documentation file by name (e.g. websocket, auth.md, docs/scheduler.md)"),
+		mcp.WithString("filename",
+			mcp.Required(),
+			mcp.Description("Documentation file name, with or without the .md extension or docs/ prefix"),
+		),
+		withVersionArg(),
+		withLocaleArg(),
+	)
+	mcpServer.AddTool(docFileTool, handleBaseDocFile)
+
+	searchTool := mcp.NewTool("base_docs_search",
+		mcp.WithDescription("Search the Base Framework documentation and return the best matching passages with file, heading and line numbers"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Keywords to search for, e.g. \"attach custom value to context\""),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of results (default 5, max 20)"),
+		),
+		withVersionArg(),
+		withLocaleArg(),
+	)
+	mcpServer.AddTool(searchTool, handleBaseDocsSearch)
+
+	sectionTool := mcp.NewTool("base_doc_section",
+		mcp.WithDescription("Get one section of a Base Framework documentation file by heading path, or the file's table of contents"),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("File name followed by headings separated by '>', e.g. \"auth.md > Authorization System > CanAccess()\". Pass just the file name for its table of contents"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("\"section\" returns the section content (default), \"toc\" returns only the headings below the path"),
+			mcp.Enum("section", "toc"),
+		),
+		withVersionArg(),
+		withLocaleArg(),
+	)
+	mcpServer.AddTool(sectionTool, handleBaseDocSection)
+
+	fieldTypesTool := mcp.NewTool("base_field_types",
+		mcp.WithDescription("Get the Base CLI field type catalog (CLI type, Go type, database column, use case, example) as JSON for building `base g` field specs"),
+		mcp.WithString("category",
+			mcp.Description("Only return types whose category contains this text, e.g. numeric, date, relationship"),
+		),
+		mcp.WithString("type",
+			mcp.Description("Only return this CLI type, e.g. decimal or belongsTo"),
+		),
+		withVersionArg(),
+		withLocaleArg(),
+	)
+	mcpServer.AddTool(fieldTypesTool, handleBaseFieldTypes)
+
+	relatedDocsTool := mcp.NewTool("base_related_docs",
+		mcp.WithDescription("Find documentation related to a doc or section through links, frontmatter links, core package mentions and similar topics. Without a path, reports broken internal links across the docs"),
+		mcp.WithString("path",
+			mcp.Description("File and optional heading path separated by \">\", e.g. \"auth.md\" or \"auth.md > Context Helpers\""),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of related docs to return (1-50, default 10)"),
+		),
+		withVersionArg(),
+		withLocaleArg(),
+	)
+	mcpServer.AddTool(relatedDocsTool, handleBaseRelatedDocs)
+
+	askTool := mcp.NewTool("base_ask",
+		mcp.WithDescription("Answer a question about Base Framework from the docs: returns the most relevant passages as an ordered evidence pack with file, heading path and line citations to quote from"),
+		mcp.WithString("question",
+			mcp.Required(),
+			mcp.Description("Natural-language question, e.g. \"How do I get the authenticated user in a controller?\""),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Number of passages to return (1-10, default 5)"),
+		),
+		withVersionArg(),
+		withLocaleArg(),
+	)
+	mcpServer.AddTool(askTool, handleBaseAsk)
+
+	symbolTool := mcp.NewTool("base_symbol",
+		mcp.WithDescription("Find every documented declaration, usage and explanation of a Base Framework identifier, indexed from the Go code blocks in the docs"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Identifier to look up, optionally qualified, e.g. CanAccess, authorization.CanAccess or router.Context"),
+		),
+		mcp.WithString("kind",
+			mcp.Description("Only return uses of this kind"),
+			mcp.Enum("func", "method", "type", "heading", "call", "composite", "reference"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of uses to return (default 30, max 200)"),
+		),
+		withVersionArg(),
+		withLocaleArg(),
+	)
+	mcpServer.AddTool(symbolTool, handleBaseSymbol)
+
+	examplesTool := mcp.NewTool("base_examples",
+		mcp.WithDescription("Browse the code examples of the Base Framework docs with their language, heading and introduction, filtered by topic and language"),
+		mcp.WithString("topic",
+			mcp.Description("Only return examples tagged with this topic, e.g. routing, auth, storage, websocket, cli, events, validation"),
+		),
+		mcp.WithString("language",
+			mcp.Description("Only return examples in this language, e.g. go, bash, env, json, yaml"),
+		),
+		mcp.WithString("query",
+			mcp.Description("Only return examples whose code, heading or introduction contains this text"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of examples to return (1-50, default 10)"),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of matching examples to skip, for paging"),
+		),
+		withVersionArg(),
+		withLocaleArg(),
+	)
+	mcpServer.AddTool(examplesTool, handleBaseExamples)
+
+	// Add tools that run the Base CLI
+	executor := NewExecutorService()
+	log.Printf("Executor: %s", strings.ReplaceAll(executor.GetStatus(), "\n", ", "))
+	registerCommandTools(mcpServer, executor)
+
+	// Add tools that list and undo the commands run on a project
+	registerJournalTools(mcpServer)
+
+	// Add tools that run the dev server in the background
+	supervisor := newServerSupervisor(executor)
+	registerServerTools(mcpServer, supervisor)
+
+	// Expose documentation files as resources
+	registerDocResources(mcpServer)
+
+	// Pick up edits to DOCS_PATH without a restart
+	if docsPath := os.Getenv("DOCS_PATH"); docsPath != "" && os.Getenv("WATCH_FILES") != "false" {
+		go watchDocs(docsPath, func() { refreshDocResources(mcpServer) })
+	}
+
+	// Add prompts for common Base Framework workflows
+	registerPrompts(mcpServer)
+
 	// Check if running in web mode (with PORT env var) or local stdio mode
 	if port := os.Getenv("PORT"); port != "" {
 		// Web mode - serve installer page
@@ -41,6 +222,11 @@ func main() {
 		mux.HandleFunc("/", serveInstaller)
 		mux.HandleFunc("/install", serveInstallScript)
 
+		// Serve documentation pages unless disabled
+		if os.Getenv("ENABLE_DOCS") != "false" {
+			mux.HandleFunc("/docs/", serveDocs)
+		}
+
 		log.Printf("Installer available at: http://localhost:%s", port)
 		if err := http.ListenAndServe(":"+port, mux); err != nil {
 			log.Fatalf("HTTP Server error: %v", err)
@@ -48,7 +234,11 @@ func main() {
 	} else {
 		// Local stdio mode for editor integration
 		log.Println("Starting stdio mode")
-		if err := server.ServeStdio(mcpServer); err != nil {
+		err := server.ServeStdio(mcpServer)
+
+		// Do not leave dev servers running once the client is gone
+		supervisor.StopAll()
+		if err != nil && !errors.Is(err, context.Canceled) {
 			log.Fatalf("Stdio error: %v", err)
 		}
 	}
@@ -63,8 +253,13 @@ func handleBaseInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.Call
 }
 
 func handleBaseCLI(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	set, err := docSetFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	// Read CLI documentation from markdown file
-	content, err := readMarkdownFile("md/docs/cli.md")
+	content, err := readMarkdownFile(set, "docs/cli.md")
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
@@ -81,43 +276,45 @@ func handleBaseCLI(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallT
 }
 
 func handleBaseDocs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Combine multiple documentation files for comprehensive docs
-	var allDocs strings.Builder
+	set, err := docSetFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	// Main overview from index.md
-	if content, err := readMarkdownFile("md/index.md"); err == nil {
-		allDocs.WriteString(content)
-		allDocs.WriteString("\n\n")
+	// Combine the requested documentation files, every file by default
+	files, err := docTopicFiles(set, request.GetStringSlice("topics", nil))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	// Core modules documentation
-	coreModules := []string{
-		"router", "emitter", "storage", "middleware",
-		"logger", "websocket", "auth", "email",
+	maxTokens := request.GetInt("max_tokens", defaultDocsMaxTokens)
+	if maxTokens < minDocsMaxTokens {
+		maxTokens = minDocsMaxTokens
 	}
 
-	allDocs.WriteString("# Core Framework Modules\n\n")
+	start, err := decodeDocsCursor(request.GetString("cursor", ""))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	for _, module := range coreModules {
-		if content, err := readMarkdownFile(fmt.Sprintf("md/docs/%s.md", module)); err == nil {
-			allDocs.WriteString(fmt.Sprintf("## %s\n\n", strings.Title(module)))
-			allDocs.WriteString(content)
-			allDocs.WriteString("\n\n")
-		}
+	page, next := paginateDocs(set, files, start, maxTokens*charsPerToken)
+
+	text := page
+	if next != nil {
+		text += fmt.Sprintf("\n\n[More documentation available: call base_docs again with cursor %q]", encodeDocsCursor(*next))
 	}
 
-	// Add configuration docs
-	if content, err := readMarkdownFile("md/docs/configuration.md"); err == nil {
-		allDocs.WriteString("# Configuration\n\n")
-		allDocs.WriteString(content)
-		allDocs.WriteString("\n\n")
+	result := map[string]any{
+		"version": set.Version,
+		"locale":  set.Locale,
+		"topics":  files,
+		"content": page,
+	}
+	if next != nil {
+		result["next_cursor"] = encodeDocsCursor(*next)
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.NewTextContent(allDocs.String()),
-		},
-	}, nil
+	return mcp.NewToolResultStructured(result, text), nil
 }
 
 func serveInstaller(w http.ResponseWriter, r *http.Request) {
@@ -176,6 +373,23 @@ func serveInstaller(w http.ResponseWriter, r *http.Request) {
             <li><strong>base_info</strong>: Get Base Framework overview and information</li>
             <li><strong>base_cli</strong>: Complete CLI commands reference with examples</li>
             <li><strong>base_docs</strong>: Comprehensive framework documentation and features</li>
+            <li><strong>base_doc_file</strong>: A single documentation file by name (e.g. <code>websocket.md</code>)</li>
+            <li><strong>base_docs_search</strong>: Ranked keyword search across all documentation</li>
+            <li><strong>base_doc_section</strong>: A single section by heading path, or a file's table of contents</li>
+            <li><strong>base_field_types</strong>: Field type catalog for <code>base g</code> as JSON</li>
+            <li><strong>base_related_docs</strong>: Related docs and sections, plus broken link report</li>
+            <li><strong>base_ask</strong>: Cited evidence passages for a question</li>
+            <li><strong>base_symbol</strong>: Documented uses of a function, type or method</li>
+            <li><strong>base_examples</strong>: Code examples by topic and language</li>
+            <li><strong>base_generate</strong>: Generate a module with <code>base g</code></li>
+            <li><strong>base_new</strong>: Create a new project with <code>base new</code></li>
+            <li><strong>base_destroy</strong>: Remove a generated module</li>
+            <li><strong>base_generate_docs</strong>: Generate Swagger docs with <code>base docs</code></li>
+            <li><strong>base_update</strong>: Update the project's core with <code>base update</code></li>
+            <li><strong>base_server_start/stop/restart</strong>: Run the dev server in the background</li>
+            <li><strong>base_server_status/logs</strong>: Check the dev server and read its output</li>
+            <li><strong>base_history</strong>: List the commands run on a project and the files they changed</li>
+            <li><strong>base_undo</strong>: Roll back the last generate, destroy, new or update commands</li>
         </ul>
     </div>
     
@@ -288,6 +502,23 @@ echo "Available tools:"
 echo "- base_info: Get Base Framework information"
 echo "- base_cli: Base CLI commands and usage"
 echo "- base_docs: Complete framework documentation"
+echo "- base_doc_file: A single documentation file by name"
+echo "- base_docs_search: Search the documentation"
+echo "- base_doc_section: A single documentation section or table of contents"
+echo "- base_field_types: Field type catalog for base g"
+echo "- base_related_docs: Related docs and broken link report"
+echo "- base_ask: Cited evidence passages for a question"
+echo "- base_symbol: Documented uses of a function, type or method"
+echo "- base_examples: Code examples by topic and language"
+echo "- base_generate: Generate a module with base g"
+echo "- base_new: Create a new project with base new"
+echo "- base_destroy: Remove a generated module"
+echo "- base_generate_docs: Generate Swagger docs with base docs"
+echo "- base_update: Update the project's core with base update"
+echo "- base_server_start/stop/restart: Run the dev server in the background"
+echo "- base_server_status/logs: Check the dev server and read its output"
+echo "- base_history: List the commands run on a project and the files they changed"
+echo "- base_undo: Roll back the last generate, destroy, new or update commands"
 `
 
 	w.Header().Set("Content-Type", "text/plain")
@@ -295,21 +526,12 @@ echo "- base_docs: Complete framework documentation"
 	fmt.Fprint(w, script)
 }
 
-func readMarkdownFile(filePath string) (string, error) {
-	// Read from embedded filesystem
-	content, err := docsFS.ReadFile(filePath)
+func readMarkdownFile(set *docSet, file string) (string, error) {
+	// Read from the doc set, dropping the YAML frontmatter
+	doc, err := set.Document(file)
 	if err != nil {
-		return "", fmt.Errorf("failed to read embedded file %s: %w", filePath, err)
-	}
-
-	// Remove YAML frontmatter if present
-	contentStr := string(content)
-	if strings.HasPrefix(contentStr, "---") {
-		parts := strings.SplitN(contentStr, "---", 3)
-		if len(parts) >= 3 {
-			contentStr = strings.TrimSpace(parts[2])
-		}
+		return "", err
 	}
 
-	return contentStr, nil
+	return doc.Body, nil
 }
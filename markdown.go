@@ -0,0 +1,271 @@
+package main
+
+import (
+	"strings"
+)
+
+// docSection is a heading-delimited passage of a markdown file. Line numbers
+// are 1-based and refer to the raw file, frontmatter included.
+type docSection struct {
+	File      string
+	DocTitle  string
+	Level     int
+	Title     string
+	Path      []string
+	StartLine int
+	EndLine   int
+	Text      string
+}
+
+// HeadingPath returns the section's heading path joined with " > "
+func (s docSection) HeadingPath() string {
+	return strings.Join(s.Path, " > ")
+}
+
+// parseHeading reports whether line is an ATX heading and returns its level and title
+func parseHeading(line string) (int, string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	if level < len(line) && line[level] != ' ' && line[level] != '\t' {
+		return 0, "", false
+	}
+
+	title := strings.TrimSpace(line[level:])
+	title = strings.TrimSpace(strings.TrimRight(title, "#"))
+	return level, title, true
+}
+
+// fenceMarker returns the fence delimiter if line opens or closes a code block
+func fenceMarker(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// isClosingFence reports whether line closes a block opened with marker.
+// Closing fences carry no info string, so "```go" never closes a block.
+func isClosingFence(line, marker string) bool {
+	return strings.Trim(strings.TrimSpace(line), marker[:1]) == ""
+}
+
+// markdownHeading is an ATX heading found outside of code blocks
+type markdownHeading struct {
+	Line  int
+	Level int
+	Title string
+}
+
+// splitLines splits file content into lines, normalising line endings
+func splitLines(content string) []string {
+	return strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+}
+
+// scanHeadings returns every heading after the frontmatter, skipping lines
+// inside fenced code blocks. Empty headings are included with an empty title.
+func scanHeadings(lines []string) []markdownHeading {
+	var headings []markdownHeading
+	fence := ""
+
+	for i := frontmatterLineCount(lines); i < len(lines); i++ {
+		if marker := fenceMarker(lines[i]); marker != "" {
+			if fence == "" {
+				fence = marker
+			} else if isClosingFence(lines[i], fence) {
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+		if level, title, ok := parseHeading(lines[i]); ok {
+			headings = append(headings, markdownHeading{Line: i + 1, Level: level, Title: title})
+		}
+	}
+
+	return headings
+}
+
+// trimTrailingBlank moves end back over blank lines, never before start
+func trimTrailingBlank(lines []string, start, end int) int {
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return end
+}
+
+// splitSections splits a markdown file into passages, one per non-empty
+// heading, plus a leading passage for any text before the first heading.
+func splitSections(file, content string) []docSection {
+	lines := splitLines(content)
+
+	var sections []docSection
+	var stack []markdownHeading
+	current := docSection{File: file, StartLine: frontmatterLineCount(lines) + 1}
+
+	flush := func(end int) {
+		current.EndLine = trimTrailingBlank(lines, current.StartLine, end)
+		if current.StartLine <= current.EndLine {
+			current.Text = strings.TrimSpace(strings.Join(lines[current.StartLine-1:current.EndLine], "\n"))
+		}
+		if current.Text != "" {
+			sections = append(sections, current)
+		}
+	}
+
+	for _, heading := range scanHeadings(lines) {
+		if heading.Title == "" {
+			continue
+		}
+		flush(heading.Line - 1)
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= heading.Level {
+			stack = stack[:len(stack)-1]
+		}
+		path := make([]string, 0, len(stack)+1)
+		for _, parent := range stack {
+			path = append(path, parent.Title)
+		}
+		path = append(path, heading.Title)
+		stack = append(stack, heading)
+
+		current = docSection{
+			File:      file,
+			Level:     heading.Level,
+			Title:     heading.Title,
+			Path:      path,
+			StartLine: heading.Line,
+		}
+	}
+
+	flush(len(lines))
+
+	return sections
+}
+
+// docHeading is a node in a file's heading tree. EndLine covers the heading's
+// own content and all of its subsections.
+type docHeading struct {
+	Level     int           `json:"level"`
+	Title     string        `json:"title"`
+	StartLine int           `json:"start_line"`
+	EndLine   int           `json:"end_line"`
+	Children  []*docHeading `json:"children,omitempty"`
+}
+
+// docOutline is the heading tree of a single markdown file
+type docOutline struct {
+	File     string
+	Meta     docMeta
+	Lines    []string
+	Headings []*docHeading
+}
+
+// parseOutline builds the heading tree (H1-H6) of a markdown file
+func parseOutline(file, content string) *docOutline {
+	lines := splitLines(content)
+	outline := &docOutline{File: file, Lines: lines}
+
+	var flat []*docHeading
+	for _, heading := range scanHeadings(lines) {
+		if heading.Title == "" {
+			continue
+		}
+		flat = append(flat, &docHeading{Level: heading.Level, Title: heading.Title, StartLine: heading.Line})
+	}
+
+	var stack []*docHeading
+	for i, node := range flat {
+		end := len(lines)
+		for _, next := range flat[i+1:] {
+			if next.Level <= node.Level {
+				end = next.StartLine - 1
+				break
+			}
+		}
+		node.EndLine = trimTrailingBlank(lines, node.StartLine, end)
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= node.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			outline.Headings = append(outline.Headings, node)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, node)
+	}
+
+	return outline
+}
+
+// Content returns the raw markdown of a heading and its subsections
+func (o *docOutline) Content(h *docHeading) string {
+	return strings.Join(o.Lines[h.StartLine-1:h.EndLine], "\n")
+}
+
+// Find resolves a heading path such as ["Authorization System", "CanAccess()"].
+// Each segment matches a heading anywhere below the previous match, so
+// intermediate levels may be skipped. Exact title matches win over partial
+// ones. The returned slice is the full path from the top-level heading.
+func (o *docOutline) Find(segments []string) []*docHeading {
+	var path []*docHeading
+	candidates := o.Headings
+
+	for _, segment := range segments {
+		want := normalizeHeading(segment)
+		if want == "" {
+			continue
+		}
+
+		trail := findHeading(candidates, want, true)
+		if trail == nil {
+			trail = findHeading(candidates, want, false)
+		}
+		if trail == nil {
+			return nil
+		}
+
+		path = append(path, trail...)
+		candidates = trail[len(trail)-1].Children
+	}
+
+	return path
+}
+
+// findHeading returns the trail of headings leading to the first match,
+// preferring a heading of nodes over nested ones, so that a "## Usage"
+// section wins over a "### Usage" inside an earlier sibling
+func findHeading(nodes []*docHeading, want string, exact bool) []*docHeading {
+	for _, node := range nodes {
+		title := normalizeHeading(node.Title)
+		if title == want || (!exact && strings.Contains(title, want)) {
+			return []*docHeading{node}
+		}
+	}
+	for _, node := range nodes {
+		if trail := findHeading(node.Children, want, exact); trail != nil {
+			return append([]*docHeading{node}, trail...)
+		}
+	}
+	return nil
+}
+
+// normalizeHeading lowercases a heading title and collapses whitespace
+func normalizeHeading(title string) string {
+	title = strings.ReplaceAll(title, "&amp;", "&")
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
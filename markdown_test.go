@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const testOutlineDoc = `# Scheduler
+## Features
+### Task Management
+Summary.
+## Task Management
+### Run Tasks
+Details.
+`
+
+func TestOutlineFind(t *testing.T) {
+	outline := parseOutline("docs/scheduler.md", testOutlineDoc)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"Scheduler > Task Management > Run Tasks", "Scheduler > Task Management > Run Tasks"},
+		{"Scheduler > Task Management", "Scheduler > Task Management"},
+		{"Scheduler > Features > Task Management", "Scheduler > Features > Task Management"},
+		{"Run Tasks", "Scheduler > Task Management > Run Tasks"},
+		{"Scheduler > run", "Scheduler > Task Management > Run Tasks"},
+		{"Scheduler > Missing", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var titles []string
+			for _, h := range outline.Find(splitHeadingPath(tt.path)) {
+				titles = append(titles, h.Title)
+			}
+			if got := strings.Join(titles, " > "); got != tt.want {
+				t.Errorf("Find(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	// The level 2 section wins over the nested heading of the same name
+	trail := outline.Find(splitHeadingPath("Scheduler > Task Management"))
+	if len(trail) != 2 || trail[1].Level != 2 {
+		t.Errorf("Find(Scheduler > Task Management) did not return the level 2 section")
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// docsWatchInterval is how often DOCS_PATH is polled for changes
+const docsWatchInterval = 2 * time.Second
+
+// overlayFS stacks file systems. Files are read from the first layer that has
+// them, directory listings are merged, so upper layers override or extend the
+// lower ones.
+type overlayFS struct {
+	layers []fs.FS
+}
+
+// newOverlayFS returns an overlay of layers, the first one on top. A single
+// layer is returned as is.
+func newOverlayFS(layers ...fs.FS) fs.FS {
+	if len(layers) == 1 {
+		return layers[0]
+	}
+	return &overlayFS{layers: layers}
+}
+
+func (o *overlayFS) Open(name string) (fs.File, error) {
+	for _, layer := range o.layers {
+		file, err := layer.Open(name)
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (o *overlayFS) Stat(name string) (fs.FileInfo, error) {
+	for _, layer := range o.layers {
+		info, err := fs.Stat(layer, name)
+		if err == nil {
+			return info, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (o *overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	seen := make(map[string]bool)
+	var entries []fs.DirEntry
+	found := false
+
+	for _, layer := range o.layers {
+		layerEntries, err := fs.ReadDir(layer, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+
+		found = true
+		for _, entry := range layerEntries {
+			if !seen[entry.Name()] {
+				seen[entry.Name()] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// overlayLayers maps the DOCS_PATH directory onto doc set versions. A
+// directory containing version directories (v1, v2, ...) provides one layer
+// per version; any other directory overlays the newest embedded version.
+func overlayLayers(dir string, embedded map[string][]fs.FS) (map[string]fs.FS, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DOCS_PATH %s: %w", dir, err)
+	}
+
+	overlays := make(map[string]fs.FS)
+	for _, entry := range entries {
+		if entry.IsDir() && versionDirPattern.MatchString(entry.Name()) {
+			overlays[entry.Name()] = os.DirFS(filepath.Join(dir, entry.Name()))
+		}
+	}
+	if len(overlays) > 0 {
+		return overlays, nil
+	}
+
+	latest := ""
+	for version := range embedded {
+		if latest == "" || versionNumber(version) > versionNumber(latest) {
+			latest = version
+		}
+	}
+	if latest == "" {
+		return nil, fmt.Errorf("DOCS_PATH %s has no version directories and there is no embedded version to overlay", dir)
+	}
+
+	overlays[latest] = os.DirFS(dir)
+	return overlays, nil
+}
+
+// versionNumber returns the major number of a version name such as "v2"
+func versionNumber(version string) int {
+	n, _ := strconv.Atoi(version[1:])
+	return n
+}
+
+// reloadDocSets rebuilds the doc sets from the embedded docs and DOCS_PATH,
+// keeping the current default version when it is still available
+func reloadDocSets() error {
+	sets, err := loadDocSets(docsFS, docsRoot, os.Getenv("DOCS_PATH"))
+	if err != nil {
+		return err
+	}
+
+	docSetsMu.Lock()
+	defer docSetsMu.Unlock()
+
+	docSets = sets
+	if _, ok := sets[defaultDocVersion]; !ok {
+		versions := sortVersions(sets)
+		defaultDocVersion = versions[len(versions)-1]
+	}
+	return nil
+}
+
+// docsSnapshot records the size and modification time of every file under a
+// directory, used to detect changes between polls
+type docsSnapshot map[string]string
+
+func snapshotDocs(dir string) docsSnapshot {
+	snapshot := make(docsSnapshot)
+	filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		snapshot[p] = fmt.Sprintf("%t %d %d", d.IsDir(), info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	return snapshot
+}
+
+func (s docsSnapshot) equal(other docsSnapshot) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for p, stamp := range s {
+		if other[p] != stamp {
+			return false
+		}
+	}
+	return true
+}
+
+// watchDocs polls dir and calls onChange after the doc sets have been
+// reloaded following a change
+func watchDocs(dir string, onChange func()) {
+	last := snapshotDocs(dir)
+
+	ticker := time.NewTicker(docsWatchInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		current := snapshotDocs(dir)
+		if current.equal(last) {
+			continue
+		}
+		last = current
+
+		if err := reloadDocSets(); err != nil {
+			log.Printf("Failed to reload docs from %s: %v", dir, err)
+			continue
+		}
+		log.Printf("Reloaded docs from %s", dir)
+		onChange()
+	}
+}
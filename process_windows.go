@@ -0,0 +1,28 @@
+//go:build windows
+
+package main
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in a process group of its own
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// killProcessGroup kills a started cmd and every process it spawned
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
+
+// terminateProcessGroup stops a started cmd and every process it spawned.
+// Console processes cannot be asked to exit, so they are killed.
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	return killProcessGroup(cmd)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// methodNotificationProgress reports the progress of a request to the client
+const methodNotificationProgress = "notifications/progress"
+
+// progressInterval is the least time between two progress notifications
+// of a command
+const progressInterval = 200 * time.Millisecond
+
+// commandProgress relays the output lines of a Base CLI command run by a
+// tool call to the client: as notifications/progress when the call carries
+// a progress token, at most one per progressInterval with the latest line,
+// and as logging notifications at the info level, which clients see once
+// they lower their level with logging/setLevel. Redrawn lines such as
+// progress bars are only logged in their final state.
+type commandProgress struct {
+	ctx     context.Context
+	srv     *server.MCPServer
+	token   mcp.ProgressToken
+	command string
+	lines   int
+
+	pending string
+	sent    time.Time
+}
+
+// withCommandProgress returns a context under which the output of the
+// command run for request is relayed to the client while it runs
+func withCommandProgress(ctx context.Context, request mcp.CallToolRequest, command []string) context.Context {
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return ctx
+	}
+
+	progress := &commandProgress{
+		ctx:     ctx,
+		srv:     srv,
+		command: strings.Join(command, " "),
+	}
+	if request.Params.Meta != nil {
+		progress.token = request.Params.Meta.ProgressToken
+	}
+	return withCommandOutput(ctx, progress)
+}
+
+// Line relays one output line. Lines, redrawn ones included, are counted
+// as progress since the length of the output is not known in advance.
+func (p *commandProgress) Line(line string, redraw bool) {
+	p.lines++
+	if !redraw {
+		p.srv.SendLogMessageToClient(p.ctx, mcp.NewLoggingMessageNotification(mcp.LoggingLevelInfo, p.command, line))
+	}
+
+	if p.token == nil {
+		return
+	}
+	p.pending = line
+	if time.Since(p.sent) >= progressInterval {
+		p.notify()
+	}
+}
+
+// Flush sends the last line held back by the throttling
+func (p *commandProgress) Flush() {
+	if p.token != nil && p.pending != "" {
+		p.notify()
+	}
+}
+
+func (p *commandProgress) notify() {
+	p.srv.SendNotificationToClient(p.ctx, methodNotificationProgress, map[string]any{
+		"progressToken": p.token,
+		"progress":      p.lines,
+		"message":       p.pending,
+	})
+	p.pending = ""
+	p.sent = time.Now()
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// rootsTimeout bounds the roots/list request sent to clients
+const rootsTimeout = 5 * time.Second
+
+// isBaseProject reports whether dir holds the go.mod of a Base project: one
+// that requires base-core, or the framework repository itself (module "base"
+// with core/ and app/ directories)
+func isBaseProject(dir string) bool {
+	if version, ok := goModBaseVersion(filepath.Join(dir, "go.mod")); !ok {
+		return false
+	} else if version != "" {
+		return true
+	}
+
+	modulePath, err := goModModulePath(filepath.Join(dir, "go.mod"))
+	if err != nil || modulePath != "base" {
+		return false
+	}
+	for _, sub := range []string{"core", "app"} {
+		if info, err := os.Stat(filepath.Join(dir, sub)); err != nil || !info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
+
+// findBaseProject walks up from dir to the nearest Base project root
+func findBaseProject(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		if isBaseProject(dir) {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// clientRoots returns the local directories the client exposes as MCP roots,
+// or nil when the client does not support roots
+func clientRoots(ctx context.Context) []string {
+	srv := server.ServerFromContext(ctx)
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if srv == nil || !ok || session.GetClientCapabilities().Roots == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rootsTimeout)
+	defer cancel()
+
+	result, err := srv.RequestRoots(ctx, mcp.ListRootsRequest{})
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for _, root := range result.Roots {
+		u, err := url.Parse(root.URI)
+		if err != nil || u.Scheme != "file" {
+			continue
+		}
+		dirs = append(dirs, filepath.FromSlash(u.Path))
+	}
+	return dirs
+}
+
+// projectRootFromRequest resolves the Base project a command tool works on:
+// the project_root argument, else the first client root inside a Base
+// project, else the project around the server's working directory
+func projectRootFromRequest(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	if dir := request.GetString("project_root", ""); dir != "" {
+		root, ok := findBaseProject(dir)
+		if !ok {
+			return "", fmt.Errorf("%s is not inside a Base project (no go.mod requiring %s)", dir, baseCoreModule)
+		}
+		return root, nil
+	}
+
+	for _, dir := range clientRoots(ctx) {
+		if root, ok := findBaseProject(dir); ok {
+			return root, nil
+		}
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		if root, ok := findBaseProject(cwd); ok {
+			return root, nil
+		}
+	}
+
+	return "", errors.New("no Base project found: pass project_root, open the project as a workspace root, or start the server inside the project")
+}
+
+// workingDirFromRequest resolves the directory base new creates a project
+// in: the path argument, else the first client root, else the server's
+// working directory
+func workingDirFromRequest(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	dir := request.GetString("path", "")
+	if dir == "" {
+		if roots := clientRoots(ctx); len(roots) > 0 {
+			dir = roots[0]
+		}
+	}
+	if dir == "" {
+		return os.Getwd()
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	return dir, nil
+}
+
+// withProjectRootArg declares the optional project_root argument of the
+// command tools
+func withProjectRootArg() mcp.ToolOption {
+	return mcp.WithString("project_root",
+		mcp.Description("Directory of the Base project (or any directory inside it). Defaults to the client's workspace root or the server's working directory"),
+	)
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// promptArgument declares a single prompt argument
+type promptArgument struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// workflowPrompt is a prompt template for a common Base Framework task. The
+// referenced doc sections are attached to the prompt as embedded resources,
+// followed by the task instructions built from the arguments.
+type workflowPrompt struct {
+	Name         string
+	Description  string
+	Arguments    []promptArgument
+	Sections     []string
+	Instructions func(args map[string]string) string
+}
+
+var workflowPrompts = []workflowPrompt{
+	{
+		Name:        "new_module",
+		Description: "Scaffold a new Base Framework module with base g",
+		Arguments: []promptArgument{
+			{Name: "name", Description: "Module name, e.g. post or product", Required: true},
+			{Name: "fields", Description: "Fields the module needs, in plain words or field:type form"},
+			{Name: "description", Description: "What the module is for"},
+		},
+		Sections: []string{
+			"cli.md > base g (generate) > Syntax",
+			"cli.md > base g (generate) > What gets generated",
+			"cli.md > Field Types Reference",
+		},
+		Instructions: func(args map[string]string) string {
+			var b strings.Builder
+			fmt.Fprintf(&b, "Create a new Base Framework module named %q.\n", args["name"])
+			if args["description"] != "" {
+				fmt.Fprintf(&b, "Purpose: %s\n", args["description"])
+			}
+			if args["fields"] != "" {
+				fmt.Fprintf(&b, "Fields: %s\n", args["fields"])
+			}
+			b.WriteString("\nUsing the field type reference above, pick the correct CLI type for every field, ")
+			b.WriteString("write the exact `base g` command, and explain which files it generates. ")
+			b.WriteString("Use relationship syntax (field:belongsTo:Model etc.) for references to other modules.")
+			return b.String()
+		},
+	},
+	{
+		Name:        "add_relationship",
+		Description: "Add a relationship between two Base Framework modules",
+		Arguments: []promptArgument{
+			{Name: "module", Description: "Module that owns the relationship field, e.g. post", Required: true},
+			{Name: "related", Description: "Related model, e.g. User", Required: true},
+			{Name: "type", Description: "belongsTo, hasOne, hasMany or manyToMany", Required: true},
+			{Name: "field", Description: "Relationship field name, e.g. author"},
+		},
+		Sections: []string{
+			"cli.md > Field Types Reference > Relationship Types",
+			"cli.md > Real-World Examples",
+		},
+		Instructions: func(args map[string]string) string {
+			field := args["field"]
+			if field == "" {
+				field = strings.ToLower(args["related"])
+			}
+			return fmt.Sprintf("Add a %s relationship named %q from the %q module to %s.\n\n"+
+				"Using the relationship reference above, give the field spec (%s:%s:%s), the resulting Go type on the model, "+
+				"and the `base g` command to regenerate or extend the module. Mention any foreign key field that is created.",
+				args["type"], field, args["module"], args["related"], field, args["type"], args["related"])
+		},
+	},
+	{
+		Name:        "add_oauth_provider",
+		Description: "Configure an OAuth login provider for a Base Framework app",
+		Arguments: []promptArgument{
+			{Name: "provider", Description: "Provider name, e.g. google, github, facebook or a custom provider", Required: true},
+			{Name: "redirect_url", Description: "Callback URL, defaults to http://localhost:8100/api/oauth/<provider>/callback"},
+		},
+		Sections: []string{
+			"auth.md > OAuth Integration",
+		},
+		Instructions: func(args map[string]string) string {
+			provider := strings.ToLower(args["provider"])
+			redirect := args["redirect_url"]
+			if redirect == "" {
+				redirect = fmt.Sprintf("http://localhost:8100/api/oauth/%s/callback", provider)
+			}
+			return fmt.Sprintf("Set up %s OAuth login in a Base Framework app with callback URL %s.\n\n"+
+				"Following the OAuth documentation above, list the .env variables to add, the login endpoint clients should call, "+
+				"and, if %s is not a built-in provider, the steps to add it as a custom provider.",
+				args["provider"], redirect, args["provider"])
+		},
+	},
+	{
+		Name:        "protect_route_with_permissions",
+		Description: "Protect Base Framework routes with authorization checks",
+		Arguments: []promptArgument{
+			{Name: "resource", Description: "Resource type to protect, e.g. Post", Required: true},
+			{Name: "actions", Description: "Actions to protect, e.g. create, update, delete", Required: true},
+			{Name: "scope", Description: "\"type\" for resource-wide checks, \"instance\" for per-record checks, or a role name"},
+		},
+		Sections: []string{
+			"auth.md > Authorization System > Can()",
+			"auth.md > Authorization System > CanAccess()",
+			"auth.md > Authorization System > HasRole()",
+			"auth.md > Authorization System > Advanced Permission Checks",
+		},
+		Instructions: func(args map[string]string) string {
+			scope := args["scope"]
+			if scope == "" {
+				scope = "type"
+			}
+			return fmt.Sprintf("Protect the %s controller routes for the actions %s (scope: %s).\n\n"+
+				"Use the new authorization syntax shown above (Can, CanAccess, HasRole, CanAny, CanAll), not the legacy middleware. "+
+				"Show the updated Routes method and explain which permissions must exist for each route.",
+				args["resource"], args["actions"], scope)
+		},
+	},
+	{
+		Name:        "schedule_job",
+		Description: "Create a scheduled background task in a Base Framework module",
+		Arguments: []promptArgument{
+			{Name: "module", Description: "Module that owns the task, e.g. posts", Required: true},
+			{Name: "name", Description: "Task name, e.g. cleanup-old-posts", Required: true},
+			{Name: "schedule", Description: "When it runs, e.g. daily at 02:00, every 15 minutes, or a cron expression", Required: true},
+			{Name: "description", Description: "What the task should do"},
+		},
+		Sections: []string{
+			"scheduler.md > Quick Start",
+			"scheduler.md > Schedule Types",
+		},
+		Instructions: func(args map[string]string) string {
+			var b strings.Builder
+			fmt.Fprintf(&b, "Create a scheduled task %q in the %q module that runs %s.\n", args["name"], args["module"], args["schedule"])
+			if args["description"] != "" {
+				fmt.Fprintf(&b, "It should: %s\n", args["description"])
+			}
+			b.WriteString("\nGive the `base scheduler g` command, pick the matching schedule type from the documentation above, ")
+			b.WriteString("and implement the execute function with context cancellation and logging.")
+			return b.String()
+		},
+	},
+}
+
+// registerPrompts adds the workflow prompt library to the server
+func registerPrompts(s *server.MCPServer) {
+	for _, wp := range workflowPrompts {
+		opts := []mcp.PromptOption{mcp.WithPromptDescription(wp.Description)}
+		for _, arg := range wp.Arguments {
+			argOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(arg.Description)}
+			if arg.Required {
+				argOpts = append(argOpts, mcp.RequiredArgument())
+			}
+			opts = append(opts, mcp.WithArgument(arg.Name, argOpts...))
+		}
+		opts = append(opts, mcp.WithArgument("version", mcp.ArgumentDescription("Base Framework version of the docs to use, e.g. v2")))
+		opts = append(opts, mcp.WithArgument("locale", mcp.ArgumentDescription("Language of the docs to use, e.g. fr (defaults to English)")))
+
+		s.AddPrompt(mcp.NewPrompt(wp.Name, opts...), wp.handle)
+	}
+}
+
+func (wp workflowPrompt) handle(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := map[string]string{"version": request.Params.Arguments["version"]}
+	for _, arg := range wp.Arguments {
+		value := strings.TrimSpace(request.Params.Arguments[arg.Name])
+		if arg.Required && value == "" {
+			return nil, fmt.Errorf("missing required argument %q", arg.Name)
+		}
+		args[arg.Name] = value
+	}
+
+	set, err := getDocSet(args["version"])
+	if err != nil {
+		return nil, err
+	}
+	set = set.Localized(request.Params.Arguments["locale"])
+
+	var messages []mcp.PromptMessage
+	for _, ref := range wp.Sections {
+		outline, trail, err := set.Section(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(mcp.TextResourceContents{
+			URI:      docSectionURI(set, outline.File, headingTitles(trail)),
+			MIMEType: docsResourceMIME,
+			Text:     outline.Content(trail[len(trail)-1]),
+		})))
+	}
+
+	messages = append(messages, mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(wp.Instructions(args))))
+
+	return mcp.NewGetPromptResult(wp.Description, messages), nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	resourceScheme       = "base://"
+	docsResourceMIME     = "text/markdown"
+	docsResourceTemplate = resourceScheme + "docs/{name}{?version,locale}{#section}"
+)
+
+// docResourceURI maps a file in a doc set to its resource URI, e.g.
+// "docs/router.md" to "base://docs/router" and "index.md" to "base://index"
+func docResourceURI(file string) string {
+	return resourceScheme + strings.ToLower(strings.TrimSuffix(file, ".md"))
+}
+
+// docSectionURI builds the template URI addressing the section at a heading
+// path of a file in a specific doc set version and locale, or the whole file
+// when the path is empty. The template only covers docs/, so files outside
+// it get their plain resource URI.
+func docSectionURI(set *docSet, file string, headingPath []string) string {
+	if !strings.HasPrefix(file, "docs/") {
+		return docResourceURI(file)
+	}
+
+	uri := docResourceURI(file) + "?version=" + url.QueryEscape(set.Version)
+	if set.Locale != defaultLocale {
+		uri += "&locale=" + url.QueryEscape(set.Locale)
+	}
+	if len(headingPath) == 0 {
+		return uri
+	}
+	return uri + "#" + url.PathEscape(strings.Join(headingPath, " > "))
+}
+
+// docResource describes a documentation file as an MCP resource, using its
+// frontmatter title and description when present
+func docResource(set *docSet, file string) mcp.Resource {
+	name := strings.TrimSuffix(path.Base(file), ".md")
+	description := fmt.Sprintf("Base Framework documentation: %s", file)
+
+	if doc, err := set.Document(file); err == nil {
+		name = doc.DisplayTitle()
+		if d := doc.DisplayDescription(); d != "" {
+			description = d
+		}
+	}
+
+	return mcp.NewResource(docResourceURI(file), name,
+		mcp.WithResourceDescription(description),
+		mcp.WithMIMEType(docsResourceMIME),
+	)
+}
+
+var (
+	docResourcesMu sync.Mutex
+	docResources   []mcp.Resource
+)
+
+// registerDocResources exposes every file of the default doc set as a
+// resource, plus a template for addressing files and sections of any version
+func registerDocResources(s *server.MCPServer) {
+	refreshDocResources(s)
+
+	template := mcp.NewResourceTemplate(docsResourceTemplate, "Base Framework documentation",
+		mcp.WithTemplateDescription(fmt.Sprintf("A documentation file by name, optionally for a specific version (%s) and locale (%s, falling back to English per file) and narrowed to a section by heading path, e.g. base://docs/auth?version=%s#Authorization%%20System%%20%%3E%%20CanAccess()", strings.Join(docVersions(), ", "), strings.Join(defaultDocSet().Locales(), ", "), defaultDocSet().Version)),
+		mcp.WithTemplateMIMEType(docsResourceMIME),
+	)
+	s.AddResourceTemplate(template, handleDocResourceTemplate)
+}
+
+// refreshDocResources replaces the registered resources with the files of the
+// current default doc set. SetResources notifies clients with
+// notifications/resources/list_changed, so it is only called when the list
+// actually differs.
+func refreshDocResources(s *server.MCPServer) {
+	set := defaultDocSet()
+
+	var resources []mcp.Resource
+	for _, file := range set.Files() {
+		resources = append(resources, docResource(set, file))
+	}
+
+	docResourcesMu.Lock()
+	defer docResourcesMu.Unlock()
+
+	if docResources != nil && reflect.DeepEqual(resources, docResources) {
+		return
+	}
+	docResources = resources
+
+	entries := make([]server.ServerResource, len(resources))
+	for i, resource := range resources {
+		entries[i] = server.ServerResource{Resource: resource, Handler: handleDocResource}
+	}
+	s.SetResources(entries...)
+}
+
+func handleDocResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	name := strings.TrimPrefix(request.Params.URI, resourceScheme)
+	return readDocResource(request.Params.URI, defaultDocSet(), name)
+}
+
+// readDocResource returns the body of a documentation file as resource contents
+func readDocResource(uri string, set *docSet, name string) ([]mcp.ResourceContents, error) {
+	file, ok := set.Resolve(name)
+	if !ok {
+		return nil, fmt.Errorf("documentation resource %s not found", uri)
+	}
+
+	content, err := readMarkdownFile(set, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: docsResourceMIME,
+			Text:     content,
+		},
+	}, nil
+}
+
+func handleDocResourceTemplate(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	name := templateArgument(request.Params.Arguments, "name")
+	section := templateArgument(request.Params.Arguments, "section")
+
+	set, err := getDocSet(templateArgument(request.Params.Arguments, "version"))
+	if err != nil {
+		return nil, err
+	}
+	set = set.Localized(templateArgument(request.Params.Arguments, "locale"))
+
+	if section == "" {
+		return readDocResource(request.Params.URI, set, "docs/"+name)
+	}
+
+	outline, trail, err := set.Section("docs/" + name + " > " + section)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: docsResourceMIME,
+			Text:     outline.Content(trail[len(trail)-1]),
+		},
+	}, nil
+}
+
+// templateArgument returns a decoded URI template variable
+func templateArgument(arguments map[string]any, key string) string {
+	var value string
+	switch v := arguments[key].(type) {
+	case string:
+		value = v
+	case []string:
+		value = strings.Join(v, ",")
+	}
+
+	value = strings.TrimPrefix(value, "#")
+	if decoded, err := url.PathUnescape(value); err == nil {
+		value = decoded
+	}
+	return value
+}
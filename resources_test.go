@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestDocSectionURI(t *testing.T) {
+	english := &docSet{Version: "v2", Locale: defaultLocale}
+	french := &docSet{Version: "v2", Locale: "fr"}
+
+	tests := []struct {
+		name string
+		set  *docSet
+		file string
+		path []string
+		want string
+	}{
+		{"section", english, "docs/router.md", []string{"Router", "Groups"}, resourceScheme + "docs/router?version=v2#Router%20%3E%20Groups"},
+		{"before the first heading", english, "docs/router.md", nil, resourceScheme + "docs/router?version=v2"},
+		{"translation", french, "docs/auth.md", []string{"Auth"}, resourceScheme + "docs/auth?version=v2&locale=fr#Auth"},
+		{"translation before the first heading", french, "docs/auth.md", nil, resourceScheme + "docs/auth?version=v2&locale=fr"},
+		{"root file", english, "index.md", nil, resourceScheme + "index"},
+		{"root file section", french, "README.md", []string{"Base"}, resourceScheme + "readme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := docSectionURI(tt.set, tt.file, tt.path); got != tt.want {
+				t.Errorf("docSectionURI() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocSectionURIReadable(t *testing.T) {
+	if err := initDocSets(); err != nil {
+		t.Fatal(err)
+	}
+	set := defaultDocSet()
+
+	s := server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(true, true))
+	registerDocResources(s)
+
+	uris := make(map[string]bool)
+	for _, section := range set.Sections() {
+		uris[docSectionURI(set, section.File, nil)] = true
+		if len(section.Path) > 0 {
+			uris[docSectionURI(set, section.File, section.Path)] = true
+		}
+	}
+
+	for uri := range uris {
+		request, _ := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "resources/read",
+			"params":  map[string]any{"uri": uri},
+		})
+		response := s.HandleMessage(context.Background(), request)
+		if err, ok := response.(mcp.JSONRPCError); ok {
+			t.Errorf("reading %s: %s", uri, err.Error.Message)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// BM25 tuning parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "can": true, "do": true, "does": true, "for": true,
+	"from": true, "how": true, "i": true, "if": true, "in": true, "into": true,
+	"is": true, "it": true, "of": true, "on": true, "or": true, "that": true,
+	"the": true, "this": true, "to": true, "use": true, "what": true, "when": true,
+	"where": true, "which": true, "with": true, "you": true, "your": true,
+}
+
+type posting struct {
+	passage int
+	freq    int
+}
+
+// searchIndex is an inverted index over documentation passages
+type searchIndex struct {
+	passages []docSection
+	postings map[string][]posting
+	lengths  []int
+	avgLen   float64
+}
+
+// searchHit is a single ranked search result
+type searchHit struct {
+	File      string  `json:"file"`
+	Title     string  `json:"title"`
+	Heading   string  `json:"heading"`
+	StartLine int     `json:"start_line"`
+	EndLine   int     `json:"end_line"`
+	Score     float64 `json:"score"`
+	Snippet   string  `json:"snippet"`
+}
+
+// tokenize lowercases text and splits it into search terms
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len(field) < 2 || stopWords[field] {
+			continue
+		}
+		tokens = append(tokens, stem(field))
+	}
+	return tokens
+}
+
+// stem applies light plural stripping so "routes" matches "route"
+func stem(term string) string {
+	switch {
+	case len(term) > 4 && strings.HasSuffix(term, "ies"):
+		return term[:len(term)-3] + "y"
+	case len(term) > 3 && strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss"):
+		return term[:len(term)-1]
+	}
+	return term
+}
+
+// newSearchIndex builds an inverted index over the given passages
+func newSearchIndex(passages []docSection) *searchIndex {
+	idx := &searchIndex{
+		passages: passages,
+		postings: make(map[string][]posting),
+		lengths:  make([]int, len(passages)),
+	}
+
+	total := 0
+	for i, passage := range passages {
+		tokens := tokenize(passage.HeadingPath() + "\n" + passage.Text)
+
+		freqs := make(map[string]int)
+		for _, token := range tokens {
+			freqs[token]++
+		}
+		for term, freq := range freqs {
+			idx.postings[term] = append(idx.postings[term], posting{passage: i, freq: freq})
+		}
+
+		idx.lengths[i] = len(tokens)
+		total += len(tokens)
+	}
+
+	if len(passages) > 0 {
+		idx.avgLen = float64(total) / float64(len(passages))
+	}
+
+	return idx
+}
+
+// idf returns the inverse document frequency of term
+func (idx *searchIndex) idf(term string) float64 {
+	n := float64(len(idx.passages))
+	df := float64(len(idx.postings[term]))
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// scores returns the BM25 score of every passage matching any of terms
+func (idx *searchIndex) scores(terms []string) map[int]float64 {
+	scores := make(map[int]float64)
+
+	for _, term := range terms {
+		postings := idx.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		idf := idx.idf(term)
+		for _, p := range postings {
+			tf := float64(p.freq)
+			norm := 1 - bm25B + bm25B*float64(idx.lengths[p.passage])/idx.avgLen
+			scores[p.passage] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+	}
+
+	return scores
+}
+
+// rank orders the scored passages, best first, and keeps at most limit
+func rank(scores map[int]float64, limit int) []int {
+	ranked := make([]int, 0, len(scores))
+	for i := range scores {
+		ranked = append(ranked, i)
+	}
+	sort.Slice(ranked, func(a, b int) bool {
+		if scores[ranked[a]] != scores[ranked[b]] {
+			return scores[ranked[a]] > scores[ranked[b]]
+		}
+		return ranked[a] < ranked[b]
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// Search returns the passages matching query ranked by BM25 score
+func (idx *searchIndex) Search(query string, limit int) []searchHit {
+	terms := uniqueTerms(tokenize(query))
+	scores := idx.scores(terms)
+	ranked := rank(scores, limit)
+
+	hits := make([]searchHit, 0, len(ranked))
+	for _, i := range ranked {
+		passage := idx.passages[i]
+		hits = append(hits, searchHit{
+			File:      passage.File,
+			Title:     passage.DocTitle,
+			Heading:   passage.HeadingPath(),
+			StartLine: passage.StartLine,
+			EndLine:   passage.EndLine,
+			Score:     math.Round(scores[i]*1000) / 1000,
+			Snippet:   snippet(passage.Text, terms),
+		})
+	}
+
+	return hits
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool)
+	unique := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
+
+// snippet returns the few lines of text that contain the most query terms
+func snippet(text string, terms []string) string {
+	const window = 3
+	const maxLen = 300
+
+	lines := strings.Split(text, "\n")
+	best, bestScore := 0, -1
+
+	for i := range lines {
+		seen := make(map[string]bool)
+		for j := i; j < i+window && j < len(lines); j++ {
+			for _, token := range tokenize(lines[j]) {
+				seen[token] = true
+			}
+		}
+
+		score := 0
+		for _, term := range terms {
+			if seen[term] {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	end := best + window
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	result := strings.TrimSpace(strings.Join(lines[best:end], "\n"))
+	if len(result) > maxLen {
+		result = strings.ToValidUTF8(result[:maxLen], "") + "..."
+	}
+	return result
+}
+
+func handleBaseDocsSearch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	limit := request.GetInt("limit", 5)
+	switch {
+	case limit < 1:
+		limit = 5
+	case limit > 20:
+		limit = 20
+	}
+
+	set, err := docSetFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	hits := set.Index().Search(query, limit)
+
+	var text strings.Builder
+	if len(hits) == 0 {
+		text.WriteString(fmt.Sprintf("No documentation matches %q", query))
+	}
+	for i, hit := range hits {
+		text.WriteString(fmt.Sprintf("%d. %s (lines %d-%d) — %s\n", i+1, hit.File, hit.StartLine, hit.EndLine, hit.Heading))
+		text.WriteString(hit.Snippet)
+		text.WriteString("\n\n")
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{
+		"query":   query,
+		"version": set.Version,
+		"hits":    hits,
+	}, strings.TrimSpace(text.String())), nil
+}
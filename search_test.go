@@ -0,0 +1,144 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"How do I define Routes?", []string{"define", "route"}},
+		{"Entities and categories", []string{"entity", "category"}},
+		{"the class address", []string{"class", "address"}},
+		{"user_id, x, GET /posts", []string{"user_id", "get", "post"}},
+		{"what is the", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func testSearchIndex() *searchIndex {
+	return newSearchIndex([]docSection{
+		{
+			File: "docs/routing.md", DocTitle: "Routing", Path: []string{"Routing", "Defining routes"},
+			StartLine: 5, EndLine: 12,
+			Text: "Register a route in the module.\nEach route maps a path to a handler.\nRoutes are grouped by module.",
+		},
+		{
+			File: "docs/modules.md", DocTitle: "Modules", Path: []string{"Modules"},
+			StartLine: 1, EndLine: 20,
+			Text: "A module bundles models, services and controllers.\nThe controller registers a route for each action.\nModules are listed in app/init.go.\nEach module has its own package.\nModules can depend on other modules.",
+		},
+		{
+			File: "docs/storage.md", DocTitle: "Storage", Path: []string{"Storage"},
+			StartLine: 1, EndLine: 8,
+			Text: "Attachments are stored on the local disk or S3.",
+		},
+	})
+}
+
+func TestSearchRanking(t *testing.T) {
+	idx := testSearchIndex()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"routes", []string{"docs/routing.md", "docs/modules.md"}},
+		{"module", []string{"docs/modules.md", "docs/routing.md"}},
+		{"attachment storage", []string{"docs/storage.md"}},
+		{"how do I", nil},
+		{"websocket", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []string
+			for _, hit := range idx.Search(tt.query, 10) {
+				got = append(got, hit.File)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchHit(t *testing.T) {
+	hits := testSearchIndex().Search("route handler", 1)
+	if len(hits) != 1 {
+		t.Fatalf("Search returned %d hits, want 1", len(hits))
+	}
+
+	hit := hits[0]
+	if hit.File != "docs/routing.md" || hit.Title != "Routing" || hit.Heading != "Routing > Defining routes" {
+		t.Errorf("hit = %+v, want the routing passage", hit)
+	}
+	if hit.StartLine != 5 || hit.EndLine != 12 {
+		t.Errorf("lines = %d-%d, want 5-12", hit.StartLine, hit.EndLine)
+	}
+	if hit.Score <= 0 {
+		t.Errorf("Score = %v, want > 0", hit.Score)
+	}
+	if !strings.Contains(hit.Snippet, "handler") {
+		t.Errorf("Snippet = %q, want the line mentioning handler", hit.Snippet)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := "Intro line.\nNothing here.\nStill nothing.\nMore filler.\nConfigure the database driver.\nSet the database URL.\nTrailing line."
+
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  string
+	}{
+		{
+			name:  "first window with most terms",
+			text:  text,
+			terms: tokenize("database driver url"),
+			want:  "More filler.\nConfigure the database driver.\nSet the database URL.",
+		},
+		{
+			name:  "no match starts at the top",
+			text:  text,
+			terms: tokenize("websocket"),
+			want:  "Intro line.\nNothing here.\nStill nothing.",
+		},
+		{
+			name:  "short text",
+			text:  "  One line only.  ",
+			terms: tokenize("line"),
+			want:  "One line only.",
+		},
+		{
+			name:  "truncated",
+			text:  strings.Repeat("x", 400),
+			terms: tokenize("x"),
+			want:  strings.Repeat("x", 300) + "...",
+		},
+		{
+			name:  "truncated on a rune boundary",
+			text:  strings.Repeat("x", 299) + "é" + strings.Repeat("x", 10),
+			terms: tokenize("x"),
+			want:  strings.Repeat("x", 299) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snippet(tt.text, tt.terms); got != tt.want {
+				t.Errorf("snippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// splitHeadingPath splits "auth.md > Authorization System > Can()" into its segments
+func splitHeadingPath(p string) []string {
+	var segments []string
+	for _, segment := range strings.Split(p, ">") {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// writeTOC renders headings as an indented list with line ranges
+func writeTOC(b *strings.Builder, headings []*docHeading, depth int) {
+	for _, h := range headings {
+		b.WriteString(fmt.Sprintf("%s- %s (lines %d-%d)\n", strings.Repeat("  ", depth), h.Title, h.StartLine, h.EndLine))
+		writeTOC(b, h.Children, depth+1)
+	}
+}
+
+func headingTitles(trail []*docHeading) []string {
+	titles := make([]string, len(trail))
+	for i, h := range trail {
+		titles[i] = h.Title
+	}
+	return titles
+}
+
+func handleBaseDocSection(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	p, err := request.RequireString("path")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	mode := request.GetString("mode", "section")
+
+	segments := splitHeadingPath(p)
+	if len(segments) == 0 {
+		return mcp.NewToolResultError("path must start with a documentation file name, e.g. \"auth.md > Authorization System\""), nil
+	}
+
+	set, err := docSetFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	outline, err := set.Outline(segments[0])
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	headings := outline.Headings
+	var trail []*docHeading
+	if len(segments) > 1 {
+		trail = outline.Find(segments[1:])
+		if trail == nil {
+			var toc strings.Builder
+			writeTOC(&toc, outline.Headings, 0)
+			return mcp.NewToolResultErrorf("Section %q not found in %s. Available sections:\n%s", strings.Join(segments[1:], " > "), outline.File, toc.String()), nil
+		}
+		headings = trail[len(trail)-1].Children
+	}
+
+	if mode == "toc" || trail == nil {
+		var toc strings.Builder
+		writeTOC(&toc, headings, 0)
+		return mcp.NewToolResultStructured(map[string]any{
+			"file":     outline.File,
+			"version":  set.Version,
+			"metadata": outline.Meta,
+			"path":     headingTitles(trail),
+			"headings": headings,
+		}, fmt.Sprintf("Table of contents for %s\n\n%s", outline.File, toc.String())), nil
+	}
+
+	section := trail[len(trail)-1]
+	content := outline.Content(section)
+
+	return mcp.NewToolResultStructured(map[string]any{
+		"file":       outline.File,
+		"version":    set.Version,
+		"metadata":   outline.Meta,
+		"path":       headingTitles(trail),
+		"start_line": section.StartLine,
+		"end_line":   section.EndLine,
+		"content":    content,
+	}, fmt.Sprintf("%s > %s (lines %d-%d)\n\n%s", outline.File, strings.Join(headingTitles(trail), " > "), section.StartLine, section.EndLine, content)), nil
+}
@@ -0,0 +1,477 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// devServerLogLines is how many output lines are kept per dev server
+	devServerLogLines = 2000
+
+	// devServerStopTimeout is how long a dev server may take to exit after
+	// SIGTERM before it is killed
+	devServerStopTimeout = 5 * time.Second
+
+	// defaultLogLines is how many lines base_server_logs returns by default
+	defaultLogLines = 100
+)
+
+// logBuffer is an io.Writer keeping the last lines written to it
+type logBuffer struct {
+	mu      sync.Mutex
+	lines   []string
+	next    int
+	full    bool
+	partial strings.Builder
+	total   int
+}
+
+func newLogBuffer(size int) *logBuffer {
+	return &logBuffer{lines: make([]string, size)}
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, c := range string(p) {
+		if c != '\n' {
+			b.partial.WriteRune(c)
+			continue
+		}
+		b.add(strings.TrimSuffix(b.partial.String(), "\r"))
+		b.partial.Reset()
+	}
+	return len(p), nil
+}
+
+func (b *logBuffer) add(line string) {
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % len(b.lines)
+	if b.next == 0 {
+		b.full = true
+	}
+	b.total++
+}
+
+// Tail returns up to n of the last lines, including an unterminated one,
+// and the number of lines written so far
+func (b *logBuffer) Tail(n int) ([]string, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var lines []string
+	if b.full {
+		lines = append(lines, b.lines[b.next:]...)
+	}
+	lines = append(lines, b.lines[:b.next]...)
+	if b.partial.Len() > 0 {
+		lines = append(lines, b.partial.String())
+	}
+
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, b.total
+}
+
+// devServer is a `base start` process run for a project
+type devServer struct {
+	Root      string
+	Reload    bool
+	Docs      bool
+	cmd       *exec.Cmd
+	logs      *logBuffer
+	startedAt time.Time
+
+	done     chan struct{}
+	exitedAt time.Time
+	exitErr  error
+}
+
+// running reports whether the process has not exited yet
+func (d *devServer) running() bool {
+	select {
+	case <-d.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// status describes the server for the status tools
+func (d *devServer) status() map[string]any {
+	status := map[string]any{
+		"project_root": d.Root,
+		"command":      strings.Join(append([]string{"base"}, d.cmd.Args[1:]...), " "),
+		"pid":          d.cmd.Process.Pid,
+		"started_at":   d.startedAt.Format(time.RFC3339),
+	}
+
+	if d.running() {
+		status["state"] = "running"
+		status["uptime"] = time.Since(d.startedAt).Round(time.Second).String()
+		return status
+	}
+
+	status["state"] = "exited"
+	status["exited_at"] = d.exitedAt.Format(time.RFC3339)
+	status["exit_code"] = d.cmd.ProcessState.ExitCode()
+	if d.exitErr != nil {
+		status["error"] = d.exitErr.Error()
+	}
+	return status
+}
+
+// serverSupervisor runs one dev server per project in the background
+type serverSupervisor struct {
+	executor *ExecutorService
+
+	// rootLocks holds a mutex per project root, so that starting, stopping
+	// and restarting its dev server do not interleave
+	rootLocks sync.Map
+
+	// mu guards the fields below and is never held while a process starts
+	// or stops. starting reserves the roots whose dev server is starting.
+	mu       sync.Mutex
+	servers  map[string]*devServer
+	starting map[string]bool
+	closed   bool
+}
+
+func newServerSupervisor(executor *ExecutorService) *serverSupervisor {
+	return &serverSupervisor{
+		executor: executor,
+		servers:  make(map[string]*devServer),
+		starting: make(map[string]bool),
+	}
+}
+
+func (s *serverSupervisor) lockRoot(root string) func() {
+	mu, _ := s.rootLocks.LoadOrStore(root, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// Start launches `base start` for the project at root unless it is
+// already running
+func (s *serverSupervisor) Start(ctx context.Context, root string, reload, docs bool) (*devServer, error) {
+	defer s.lockRoot(root)()
+	return s.start(ctx, root, reload, docs)
+}
+
+// start launches `base start` for the project at root. The caller holds the
+// root's lock; s.mu is only held to check and update the servers, as
+// building the CLI may take minutes.
+func (s *serverSupervisor) start(ctx context.Context, root string, reload, docs bool) (*devServer, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("the MCP server is shutting down")
+	}
+	if d, ok := s.servers[root]; ok && d.running() {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("the dev server of %s is already running (pid %d); restart it with base_server_restart", root, d.cmd.Process.Pid)
+	}
+	s.starting[root] = true
+	s.mu.Unlock()
+
+	logs := newLogBuffer(devServerLogLines)
+	cmd, err := s.executor.ExecuteStart(ctx, root, reload, docs, logs)
+	if err != nil {
+		s.mu.Lock()
+		delete(s.starting, root)
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	d := &devServer{
+		Root:      root,
+		Reload:    reload,
+		Docs:      docs,
+		cmd:       cmd,
+		logs:      logs,
+		startedAt: time.Now(),
+		done:      make(chan struct{}),
+	}
+	go func() {
+		d.exitErr = cmd.Wait()
+		d.exitedAt = time.Now()
+		close(d.done)
+	}()
+
+	s.mu.Lock()
+	delete(s.starting, root)
+	closed := s.closed
+	if !closed {
+		s.servers[root] = d
+	}
+	s.mu.Unlock()
+
+	// StopAll ran while the process was starting
+	if closed {
+		d.stop()
+		return nil, fmt.Errorf("the MCP server is shutting down")
+	}
+	return d, nil
+}
+
+// Restart stops the dev server of the project at root, if any, and starts
+// it again. flags returns the flags to start with from those of the
+// previous server, or false when there was none.
+func (s *serverSupervisor) Restart(ctx context.Context, root string, flags func(reload, docs bool) (bool, bool)) (*devServer, error) {
+	defer s.lockRoot(root)()
+
+	reload, docs := false, false
+	if d, ok := s.Get(root); ok {
+		reload, docs = d.Reload, d.Docs
+		d.stop()
+	}
+
+	reload, docs = flags(reload, docs)
+	return s.start(ctx, root, reload, docs)
+}
+
+// Starting reports whether the dev server of the project at root is
+// being started
+func (s *serverSupervisor) Starting(root string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.starting[root]
+}
+
+// Stop stops the dev server of the project at root, killing it when it
+// does not exit in time
+func (s *serverSupervisor) Stop(root string) (*devServer, error) {
+	defer s.lockRoot(root)()
+
+	s.mu.Lock()
+	d, ok := s.servers[root]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no dev server has been started for %s", root)
+	}
+
+	d.stop()
+	return d, nil
+}
+
+func (d *devServer) stop() {
+	if !d.running() {
+		return
+	}
+
+	terminateProcessGroup(d.cmd)
+	select {
+	case <-d.done:
+	case <-time.After(devServerStopTimeout):
+		killProcessGroup(d.cmd)
+		<-d.done
+	}
+}
+
+// Get returns the dev server of the project at root
+func (s *serverSupervisor) Get(root string) (*devServer, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.servers[root]
+	return d, ok
+}
+
+// StopAll stops every dev server, on shutdown. Servers still starting are
+// stopped as soon as their process is up.
+func (s *serverSupervisor) StopAll() {
+	s.mu.Lock()
+	s.closed = true
+	servers := make([]*devServer, 0, len(s.servers))
+	for _, d := range s.servers {
+		servers = append(servers, d)
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, d := range servers {
+		wg.Add(1)
+		go func(d *devServer) {
+			defer wg.Done()
+			d.stop()
+		}(d)
+	}
+	wg.Wait()
+}
+
+// registerServerTools adds the tools that manage dev servers
+func registerServerTools(s *server.MCPServer, supervisor *serverSupervisor) {
+	startTool := mcp.NewTool("base_server_start",
+		mcp.WithDescription("Start the development server of a Base project (`base start`) in the background. Use base_server_logs to follow its output"),
+		mcp.WithBoolean("reload",
+			mcp.Description("Restart on code changes (-r)"),
+		),
+		mcp.WithBoolean("docs",
+			mcp.Description("Generate the Swagger docs on start (-d)"),
+		),
+		withProjectRootArg(),
+	)
+	s.AddTool(startTool, supervisor.handleStart)
+
+	stopTool := mcp.NewTool("base_server_stop",
+		mcp.WithDescription("Stop the development server of a Base project"),
+		withProjectRootArg(),
+	)
+	s.AddTool(stopTool, supervisor.handleStop)
+
+	restartTool := mcp.NewTool("base_server_restart",
+		mcp.WithDescription("Restart the development server of a Base project, keeping its flags unless new ones are given"),
+		mcp.WithBoolean("reload",
+			mcp.Description("Restart on code changes (-r)"),
+		),
+		mcp.WithBoolean("docs",
+			mcp.Description("Generate the Swagger docs on start (-d)"),
+		),
+		withProjectRootArg(),
+	)
+	s.AddTool(restartTool, supervisor.handleRestart)
+
+	statusTool := mcp.NewTool("base_server_status",
+		mcp.WithDescription("Show whether the development server of a Base project is running, with its pid, uptime or exit code"),
+		withProjectRootArg(),
+	)
+	s.AddTool(statusTool, supervisor.handleStatus)
+
+	logsTool := mcp.NewTool("base_server_logs",
+		mcp.WithDescription("Get the latest output lines of the development server of a Base project"),
+		mcp.WithNumber("lines",
+			mcp.Description(fmt.Sprintf("Number of lines to return (default %d, at most %d are kept)", defaultLogLines, devServerLogLines)),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Only return lines containing this text, e.g. error"),
+		),
+		withProjectRootArg(),
+	)
+	s.AddTool(logsTool, supervisor.handleLogs)
+}
+
+func (s *serverSupervisor) handleStart(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	root, err := projectRootFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	d, err := s.Start(ctx, root, request.GetBool("reload", false), request.GetBool("docs", false))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultStructured(d.status(), fmt.Sprintf("Started the dev server of %s (pid %d)", root, d.cmd.Process.Pid)), nil
+}
+
+func (s *serverSupervisor) handleStop(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	root, err := projectRootFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	d, err := s.Stop(root)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultStructured(d.status(), fmt.Sprintf("Stopped the dev server of %s", root)), nil
+}
+
+func (s *serverSupervisor) handleRestart(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	root, err := projectRootFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	d, err := s.Restart(ctx, root, func(reload, docs bool) (bool, bool) {
+		return request.GetBool("reload", reload), request.GetBool("docs", docs)
+	})
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultStructured(d.status(), fmt.Sprintf("Restarted the dev server of %s (pid %d)", root, d.cmd.Process.Pid)), nil
+}
+
+func (s *serverSupervisor) handleStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	root, err := projectRootFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	d, ok := s.Get(root)
+	if !ok && s.Starting(root) {
+		return mcp.NewToolResultStructured(map[string]any{
+			"project_root": root,
+			"state":        "starting",
+		}, fmt.Sprintf("The dev server of %s is starting", root)), nil
+	}
+	if !ok {
+		return mcp.NewToolResultStructured(map[string]any{
+			"project_root": root,
+			"state":        "not started",
+		}, fmt.Sprintf("No dev server has been started for %s", root)), nil
+	}
+
+	status := d.status()
+	text := fmt.Sprintf("Dev server of %s is %s (pid %d)", root, status["state"], d.cmd.Process.Pid)
+	return mcp.NewToolResultStructured(status, text), nil
+}
+
+func (s *serverSupervisor) handleLogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	root, err := projectRootFromRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	d, ok := s.Get(root)
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("no dev server has been started for %s", root)), nil
+	}
+
+	n := request.GetInt("lines", defaultLogLines)
+	if n < 1 {
+		n = defaultLogLines
+	}
+
+	filter := strings.ToLower(request.GetString("filter", ""))
+	lines, total := d.logs.Tail(0)
+	if filter != "" {
+		matched := lines[:0:0]
+		for _, line := range lines {
+			if strings.Contains(strings.ToLower(line), filter) {
+				matched = append(matched, line)
+			}
+		}
+		lines = matched
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	if lines == nil {
+		lines = []string{}
+	}
+
+	status := d.status()
+	text := strings.Join(lines, "\n")
+	if text == "" {
+		text = fmt.Sprintf("No output from the dev server of %s yet", root)
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{
+		"project_root": root,
+		"state":        status["state"],
+		"total_lines":  total,
+		"lines":        lines,
+	}, text), nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// fakeStartCLI is a base CLI whose start command runs until it is stopped
+const fakeStartCLI = `#!/bin/sh
+[ "$1" = start ] || exit 2
+echo "Server started"
+exec sleep 60
+`
+
+func newTestSupervisor(t *testing.T) *serverSupervisor {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake CLI is a shell script")
+	}
+
+	cli := filepath.Join(t.TempDir(), "base")
+	if err := os.WriteFile(cli, []byte(fakeStartCLI), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	s := newServerSupervisor(&ExecutorService{basePath: cli, builds: make(map[string]string)})
+	t.Cleanup(s.StopAll)
+	return s
+}
+
+func TestConcurrentRestarts(t *testing.T) {
+	s := newTestSupervisor(t)
+	root := t.TempDir()
+	keep := func(reload, docs bool) (bool, bool) { return reload, docs }
+
+	if _, err := s.Start(context.Background(), root, true, false); err != nil {
+		t.Fatal(err)
+	}
+
+	const restarts = 4
+	servers := make([]*devServer, restarts)
+	errs := make([]error, restarts)
+	var wg sync.WaitGroup
+	for i := 0; i < restarts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			servers[i], errs[i] = s.Restart(context.Background(), root, keep)
+		}(i)
+	}
+	wg.Wait()
+
+	current, ok := s.Get(root)
+	if !ok || !current.running() {
+		t.Fatal("no dev server is running after the restarts")
+	}
+	if !current.Reload {
+		t.Error("the restarts dropped the reload flag")
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("restart %d: %v", i, err)
+		}
+		if servers[i] != current && servers[i].running() {
+			t.Errorf("restart %d left pid %d running", i, servers[i].cmd.Process.Pid)
+		}
+	}
+}
+
+func TestConcurrentStarts(t *testing.T) {
+	s := newTestSupervisor(t)
+	root := t.TempDir()
+
+	const starts = 4
+	errs := make([]error, starts)
+	var wg sync.WaitGroup
+	for i := 0; i < starts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = s.Start(context.Background(), root, false, false)
+		}(i)
+	}
+	wg.Wait()
+
+	started := 0
+	for _, err := range errs {
+		if err == nil {
+			started++
+		}
+	}
+	if started != 1 {
+		t.Errorf("%d of %d concurrent starts succeeded, want 1", started, starts)
+	}
+	if s.Starting(root) {
+		t.Error("the root is still reserved after the starts")
+	}
+}
+
+func TestStartAfterStopAll(t *testing.T) {
+	s := newTestSupervisor(t)
+	s.StopAll()
+
+	if _, err := s.Start(context.Background(), t.TempDir(), false, false); err == nil {
+		t.Error("Start succeeded after StopAll")
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// symbolUse is one documented occurrence of an identifier: a declaration, a
+// call, a reference or a heading naming it
+type symbolUse struct {
+	Symbol      string `json:"symbol"`
+	Kind        string `json:"kind"`
+	File        string `json:"file"`
+	Heading     string `json:"heading,omitempty"`
+	Line        int    `json:"line"`
+	Code        string `json:"code,omitempty"`
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// symbolKindOrder sorts declarations and headings before usages
+var symbolKindOrder = map[string]int{
+	"func": 0, "method": 0, "type": 0, "heading": 1, "call": 2, "composite": 3, "reference": 4,
+}
+
+// symbolIndex maps lowercased identifiers to their documented uses
+type symbolIndex struct {
+	uses map[string][]symbolUse
+}
+
+// buildSymbolIndex parses the Go code blocks of the set and records the
+// functions, types and methods they declare or use, plus headings that name
+// an indexed identifier, such as "CanAccess()"
+func buildSymbolIndex(set *docSet) *symbolIndex {
+	idx := &symbolIndex{uses: make(map[string][]symbolUse)}
+	packages := corePackages(set)
+	seen := make(map[string]bool)
+
+	add := func(name string, use symbolUse) {
+		key := strings.ToLower(name)
+		id := fmt.Sprintf("%s|%s|%d|%s|%s", key, use.File, use.Line, use.Symbol, use.Kind)
+		if name == "_" || seen[id] {
+			return
+		}
+		seen[id] = true
+		idx.uses[key] = append(idx.uses[key], use)
+	}
+
+	for _, block := range set.CodeBlocks() {
+		if block.Lang != "go" {
+			continue
+		}
+
+		fset := token.NewFileSet()
+		file, _ := parseGoSnippet(fset, "snippet.go", block.Code)
+		if file == nil {
+			continue
+		}
+
+		codeLines := strings.Split(block.Code, "\n")
+		imported := make(map[string]bool)
+		for _, spec := range file.Imports {
+			if p, err := strconv.Unquote(spec.Path.Value); err == nil {
+				imported[p[strings.LastIndex(p, "/")+1:]] = true
+			}
+		}
+		isPackage := func(name string) bool {
+			_, core := packages[name]
+			return core || imported[name]
+		}
+
+		record := func(name, symbol, kind string, pos token.Pos) {
+			line := fset.Position(pos).Line
+			if line < 1 || line > len(codeLines) {
+				return
+			}
+			add(name, symbolUse{
+				Symbol:      symbol,
+				Kind:        kind,
+				File:        block.File,
+				Heading:     strings.Join(block.Path, " > "),
+				Line:        block.StartLine + line - 1,
+				Code:        strings.TrimSpace(codeLines[line-1]),
+				Explanation: block.Context,
+			})
+		}
+
+		calls := make(map[ast.Expr]bool)
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncDecl:
+				if node.Recv != nil && len(node.Recv.List) > 0 {
+					if recv := receiverType(node.Recv.List[0].Type); recv != "" {
+						record(node.Name.Name, recv+"."+node.Name.Name, "method", node.Name.Pos())
+						return true
+					}
+				}
+				record(node.Name.Name, node.Name.Name, "func", node.Name.Pos())
+			case *ast.TypeSpec:
+				record(node.Name.Name, node.Name.Name, "type", node.Name.Pos())
+			case *ast.CallExpr:
+				fun := unwrapIndex(node.Fun)
+				calls[fun] = true
+				switch f := fun.(type) {
+				case *ast.Ident:
+					if types.Universe.Lookup(f.Name) == nil {
+						record(f.Name, f.Name, "call", f.Pos())
+					}
+				case *ast.SelectorExpr:
+					record(f.Sel.Name, types.ExprString(f), "call", f.Sel.Pos())
+				}
+			case *ast.CompositeLit:
+				switch t := node.Type.(type) {
+				case *ast.Ident:
+					record(t.Name, t.Name, "composite", t.Pos())
+				case *ast.SelectorExpr:
+					calls[t] = true
+					record(t.Sel.Name, types.ExprString(t), "composite", t.Sel.Pos())
+				}
+			case *ast.SelectorExpr:
+				if calls[node] {
+					return true
+				}
+				if x, ok := node.X.(*ast.Ident); ok && isPackage(x.Name) && ast.IsExported(node.Sel.Name) {
+					record(node.Sel.Name, types.ExprString(node), "reference", node.Sel.Pos())
+				}
+			}
+			return true
+		})
+	}
+
+	for _, section := range set.Sections() {
+		for _, word := range strings.FieldsFunc(section.Title, func(r rune) bool {
+			return !(r == '_' || r == '.' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+		}) {
+			name := word[strings.LastIndex(word, ".")+1:]
+			if _, ok := idx.uses[strings.ToLower(name)]; !ok || !ast.IsExported(name) {
+				continue
+			}
+			add(name, symbolUse{
+				Symbol:      word,
+				Kind:        "heading",
+				File:        section.File,
+				Heading:     section.HeadingPath(),
+				Line:        section.StartLine,
+				Explanation: sectionIntro(section.Text),
+			})
+		}
+	}
+
+	for key, uses := range idx.uses {
+		sort.SliceStable(uses, func(i, j int) bool {
+			if symbolKindOrder[uses[i].Kind] != symbolKindOrder[uses[j].Kind] {
+				return symbolKindOrder[uses[i].Kind] < symbolKindOrder[uses[j].Kind]
+			}
+			if uses[i].File != uses[j].File {
+				return uses[i].File < uses[j].File
+			}
+			return uses[i].Line < uses[j].Line
+		})
+		idx.uses[key] = uses
+	}
+
+	return idx
+}
+
+// receiverType returns the type name of a method receiver
+func receiverType(expr ast.Expr) string {
+	switch t := unwrapIndex(expr).(type) {
+	case *ast.StarExpr:
+		return receiverType(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// unwrapIndex strips generic instantiation, e.g. UserFromContext[*User]
+func unwrapIndex(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		return e.X
+	case *ast.IndexListExpr:
+		return e.X
+	}
+	return expr
+}
+
+// sectionIntro returns the first prose lines of a section below its heading
+func sectionIntro(text string) string {
+	var intro []string
+	for _, line := range strings.Split(text, "\n")[1:] {
+		line = strings.TrimSpace(line)
+		if fenceMarker(line) != "" || len(intro) == 3 {
+			break
+		}
+		if line != "" {
+			intro = append(intro, line)
+		}
+	}
+	return strings.Join(intro, "\n")
+}
+
+// Lookup returns the uses of an identifier such as "CanAccess",
+// "CanAccess()" or "authorization.CanAccess". A qualified name only matches
+// uses with that qualifier.
+func (idx *symbolIndex) Lookup(query string) []symbolUse {
+	query = strings.TrimSuffix(strings.TrimSpace(query), "()")
+	name := query[strings.LastIndex(query, ".")+1:]
+
+	uses := idx.uses[strings.ToLower(name)]
+	if name == query {
+		return uses
+	}
+
+	var qualified []symbolUse
+	for _, use := range uses {
+		if strings.EqualFold(use.Symbol, query) || strings.HasSuffix(strings.ToLower(use.Symbol), "."+strings.ToLower(query)) {
+			qualified = append(qualified, use)
+		}
+	}
+	return qualified
+}
+
+// Suggest returns indexed identifiers similar to query
+func (idx *symbolIndex) Suggest(query string, limit int) []string {
+	query = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(query), "()"))
+	query = query[strings.LastIndex(query, ".")+1:]
+
+	var suggestions []string
+	for key, uses := range idx.uses {
+		if query != "" && strings.Contains(key, query) {
+			suggestions = append(suggestions, uses[0].Symbol)
+		}
+	}
+	sort.Strings(suggestions)
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+func handleBaseSymbol(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	limit := request.GetInt("limit", 30)
+	switch {
+	case limit < 1:
+		limit = 30
+	case limit > 200:
+		limit = 200
+	}
+	kind := strings.ToLower(request.GetString("kind", ""))
+
+	set, err := docSetFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	idx := set.Symbols()
+	var uses []symbolUse
+	for _, use := range idx.Lookup(name) {
+		if kind == "" || use.Kind == kind {
+			uses = append(uses, use)
+		}
+	}
+
+	if len(uses) == 0 {
+		suggestions := idx.Suggest(name, 10)
+		message := fmt.Sprintf("No documented uses of %q", name)
+		if len(suggestions) > 0 {
+			message += ". Similar symbols: " + strings.Join(suggestions, ", ")
+		}
+		return mcp.NewToolResultError(message), nil
+	}
+
+	total := len(uses)
+	if len(uses) > limit {
+		uses = uses[:limit]
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%d documented uses of %s", total, name)
+	if total > len(uses) {
+		fmt.Fprintf(&text, " (showing %d)", len(uses))
+	}
+	text.WriteString("\n\n")
+
+	lastExplanation := ""
+	for _, use := range uses {
+		fmt.Fprintf(&text, "- %s:%d [%s] %s", use.File, use.Line, use.Kind, use.Symbol)
+		if use.Heading != "" {
+			fmt.Fprintf(&text, " — %s", use.Heading)
+		}
+		text.WriteString("\n")
+		if use.Code != "" {
+			fmt.Fprintf(&text, "    %s\n", use.Code)
+		}
+		if use.Explanation != "" && use.Explanation != lastExplanation {
+			fmt.Fprintf(&text, "    %s\n", strings.ReplaceAll(use.Explanation, "\n", "\n    "))
+			lastExplanation = use.Explanation
+		}
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{
+		"name":    name,
+		"version": set.Version,
+		"total":   total,
+		"uses":    uses,
+	}, strings.TrimSpace(text.String())), nil
+}
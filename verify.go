@@ -0,0 +1,416 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// docsImportPrefix is the import path prefix of core packages in doc snippets
+const docsImportPrefix = "base/core"
+
+// stdlibImports resolves package names that doc snippets use without
+// importing them
+var stdlibImports = map[string]string{
+	"bytes": "bytes", "context": "context", "errors": "errors", "filepath": "path/filepath",
+	"fmt": "fmt", "http": "net/http", "io": "io", "json": "encoding/json", "log": "log",
+	"math": "math", "os": "os", "rand": "math/rand", "regexp": "regexp", "sort": "sort",
+	"strconv": "strconv", "strings": "strings", "sync": "sync", "time": "time", "url": "net/url",
+}
+
+// Type errors that only mean a fragment depends on code around it in the docs
+var contextErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^undefined: [A-Za-z_]\w*$`),
+	regexp.MustCompile(`^declared and not used`),
+	regexp.MustCompile(`imported and not used`),
+	regexp.MustCompile(`^(too many|not enough) return values`),
+	regexp.MustCompile(`^missing return`),
+	regexp.MustCompile(`^\w+ redeclared in this block`),
+	regexp.MustCompile(`^no new variables on left side of :=`),
+	regexp.MustCompile(`undefined \(type \*?\w+ has no field or method \w+\)$`),
+	regexp.MustCompile(`^could not import `),
+}
+
+var undefinedPattern = regexp.MustCompile(`^undefined: ([A-Za-z_]\w*)$`)
+
+// exampleCheck is the verification result of one Go example
+type exampleCheck struct {
+	ID      string   `json:"id"`
+	File    string   `json:"file"`
+	Heading string   `json:"heading,omitempty"`
+	Line    int      `json:"line"`
+	Status  string   `json:"status"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// runVerifyExamples implements `base-mcp verify-examples`. It type-checks
+// every Go code block of a doc set and prints a JSON report. It returns 1
+// when an example is broken, 2 when the check could not run.
+func runVerifyExamples(args []string) int {
+	flags := flag.NewFlagSet("verify-examples", flag.ContinueOnError)
+	core := flags.String("core", os.Getenv("BASE_CORE_PATH"), "local base-core checkout or Base project to resolve base/core imports against (defaults to BASE_CORE_PATH)")
+	version := flags.String("version", "", "docs version to check (defaults to the latest)")
+	all := flags.Bool("all", false, "list passing examples too")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	sets, err := loadDocSets(docsFS, docsRoot, os.Getenv("DOCS_PATH"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify-examples: %v\n", err)
+		return 2
+	}
+	versions := sortVersions(sets)
+	set := sets[versions[len(versions)-1]]
+	if *version != "" {
+		var ok bool
+		if set, ok = sets[normalizeDocVersion(*version)]; !ok {
+			fmt.Fprintf(os.Stderr, "verify-examples: version %q not found\n", *version)
+			return 2
+		}
+	}
+
+	workDir, err := os.MkdirTemp("", "base-mcp-examples-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify-examples: %v\n", err)
+		return 2
+	}
+	defer os.RemoveAll(workDir)
+
+	corePrefix, err := prepareExampleModule(workDir, *core)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify-examples: %v\n", err)
+		return 2
+	}
+
+	checks := verifyExamples(set, workDir, corePrefix)
+
+	counts := make(map[string]int)
+	var listed []exampleCheck
+	for _, check := range checks {
+		counts[check.Status]++
+		if *all || check.Status != "ok" {
+			listed = append(listed, check)
+		}
+	}
+	if listed == nil {
+		listed = []exampleCheck{}
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	out.Encode(map[string]any{
+		"version":  set.Version,
+		"checked":  len(checks),
+		"counts":   counts,
+		"examples": listed,
+	})
+
+	if counts["broken"] > 0 || counts["syntax_error"] > 0 {
+		return 1
+	}
+	return 0
+}
+
+// prepareExampleModule writes a go.mod in dir that resolves base/core
+// imports against the local checkout at core, offline. It returns the
+// import path prefix doc imports of base/core are rewritten to, or "" when
+// no checkout is available.
+func prepareExampleModule(dir, core string) (string, error) {
+	goMod := "module snippets\n\ngo 1.21\n"
+	prefix := ""
+
+	if core != "" {
+		core, err := filepath.Abs(core)
+		if err != nil {
+			return "", err
+		}
+		modulePath, err := goModModulePath(filepath.Join(core, "go.mod"))
+		if err != nil {
+			return "", fmt.Errorf("base-core at %s: %w", core, err)
+		}
+
+		// A Base project (module "base") contains core/ itself, a base-core
+		// checkout holds the core packages at its root
+		prefix = modulePath
+		if modulePath == "base" {
+			prefix = docsImportPrefix
+		}
+		goMod += fmt.Sprintf("\nrequire %s v0.0.0\n\nreplace %s => %s\n", modulePath, modulePath, core)
+
+		if sum, err := os.ReadFile(filepath.Join(core, "go.sum")); err == nil {
+			if err := os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0o644); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		return "", err
+	}
+
+	return prefix, nil
+}
+
+// exampleModuleEnv makes go list in the example module resolve everything
+// from the module cache and never hit the network
+var exampleModuleEnv = []string{"GOFLAGS=-mod=mod", "GOPROXY=off", "GOSUMDB=off", "GOWORK=off"}
+
+// exampleImporter imports packages from the export data that go list builds
+// for them inside the example module at dir, so that its go.mod resolves
+// base/core imports. Failed lookups are remembered, as every example of a
+// missing package would run go list again.
+func exampleImporter(fset *token.FileSet, dir string) types.Importer {
+	type export struct {
+		file string
+		err  error
+	}
+	exports := make(map[string]export)
+
+	return importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		e, ok := exports[path]
+		if !ok {
+			e.file, e.err = goListExport(dir, path)
+			exports[path] = e
+		}
+		if e.err != nil {
+			return nil, e.err
+		}
+		return os.Open(e.file)
+	})
+}
+
+// goListExport builds the package at an import path in the module at dir
+// and returns its export data file
+func goListExport(dir, path string) (string, error) {
+	cmd := exec.Command("go", "list", "-export", "-f", "{{.Export}}", "--", path)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), exampleModuleEnv...)
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			// The last line holds the reason, after the lookups go tried
+			lines := strings.Split(strings.TrimSpace(string(exitErr.Stderr)), "\n")
+			return "", errors.New(lines[len(lines)-1])
+		}
+		return "", err
+	}
+
+	file := strings.TrimSpace(string(out))
+	if file == "" {
+		return "", fmt.Errorf("no export data for %s", path)
+	}
+	return file, nil
+}
+
+// goModModulePath returns the module path declared in a go.mod file
+func goModModulePath(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	return "", errors.New("go.mod has no module line")
+}
+
+// snippetImports collects the import path of every package name imported by
+// a Go block of the set, to add missing imports to fragments
+func snippetImports(blocks []codeBlock) map[string]string {
+	imports := make(map[string]string)
+	for name, p := range stdlibImports {
+		imports[name] = p
+	}
+
+	fset := token.NewFileSet()
+	for _, block := range blocks {
+		file, _ := parseGoSnippet(fset, "snippet.go", block.Code)
+		if file == nil {
+			continue
+		}
+		for _, spec := range file.Imports {
+			p, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || !strings.HasPrefix(p, docsImportPrefix+"/") {
+				continue
+			}
+			name := p[strings.LastIndex(p, "/")+1:]
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			imports[name] = p
+		}
+	}
+	return imports
+}
+
+// verifyExamples type-checks every Go block of the set as a package of its
+// own, resolving imports through the example module in workDir.
+func verifyExamples(set *docSet, workDir, corePrefix string) []exampleCheck {
+	var blocks []codeBlock
+	for _, block := range set.CodeBlocks() {
+		if block.Lang == "go" && strings.TrimSpace(block.Code) != "" {
+			blocks = append(blocks, block)
+		}
+	}
+
+	known := snippetImports(blocks)
+	fset := token.NewFileSet()
+	imp := exampleImporter(fset, workDir)
+
+	checks := make([]exampleCheck, 0, len(blocks))
+	for i, block := range blocks {
+		check := exampleCheck{
+			ID:      fmt.Sprintf("%s:%d", block.File, block.StartLine),
+			File:    block.File,
+			Heading: strings.Join(block.Path, " > "),
+			Line:    block.StartLine,
+		}
+
+		filename := fmt.Sprintf("ex%03d/snippet.go", i)
+		file, err := parseGoSnippet(fset, filename, block.Code)
+		if err != nil {
+			check.Status = "syntax_error"
+			var list scanner.ErrorList
+			if errors.As(err, &list) {
+				for _, e := range list {
+					check.Errors = append(check.Errors, fmt.Sprintf("line %d: %s", block.StartLine+e.Pos.Line-1, e.Msg))
+				}
+			} else {
+				check.Errors = []string{err.Error()}
+			}
+			checks = append(checks, check)
+			continue
+		}
+
+		rewriteCoreImports(file, corePrefix)
+		typeErrors := typeCheckSnippet(fset, imp, file)
+
+		// Add imports for packages the fragment uses without importing them
+		var missing []string
+		for _, e := range typeErrors {
+			if m := undefinedPattern.FindStringSubmatch(e.Msg); m != nil && known[m[1]] != "" && usedAsPackage(file, m[1]) {
+				missing = append(missing, m[1])
+			}
+		}
+		if len(missing) > 0 {
+			for _, name := range missing {
+				addImport(file, name, known[name])
+			}
+			rewriteCoreImports(file, corePrefix)
+			typeErrors = typeCheckSnippet(fset, imp, file)
+		}
+
+		// Without the imported packages every error may be a consequence of
+		// the missing declarations, so the example cannot be judged
+		check.Status = "ok"
+		for _, e := range typeErrors {
+			if strings.HasPrefix(e.Msg, "could not import ") {
+				check.Status = "unverified"
+				check.Errors = []string{e.Msg + "; pass -core with a local base-core checkout"}
+				break
+			}
+		}
+		if check.Status == "ok" {
+			for _, e := range typeErrors {
+				if isContextError(e.Msg) {
+					continue
+				}
+				check.Status = "broken"
+				check.Errors = append(check.Errors, fmt.Sprintf("line %d: %s", block.StartLine+fset.Position(e.Pos).Line-1, e.Msg))
+			}
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// typeCheckSnippet type-checks a single file and returns every error
+func typeCheckSnippet(fset *token.FileSet, imp types.Importer, file *ast.File) []types.Error {
+	var errs []types.Error
+	config := types.Config{
+		Importer: imp,
+		Error: func(err error) {
+			var typeErr types.Error
+			if errors.As(err, &typeErr) {
+				errs = append(errs, typeErr)
+			}
+		},
+	}
+	config.Check(file.Name.Name, fset, []*ast.File{file}, nil)
+	return errs
+}
+
+// isContextError reports whether a type error only shows that a fragment
+// relies on surrounding code, such as variables declared elsewhere
+func isContextError(msg string) bool {
+	for _, pattern := range contextErrorPatterns {
+		if pattern.MatchString(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteCoreImports points base/core imports at the local checkout
+func rewriteCoreImports(file *ast.File, corePrefix string) {
+	if corePrefix == "" || corePrefix == docsImportPrefix {
+		return
+	}
+	for _, spec := range file.Imports {
+		p, err := strconv.Unquote(spec.Path.Value)
+		if err == nil && strings.HasPrefix(p, docsImportPrefix+"/") {
+			spec.Path.Value = strconv.Quote(corePrefix + strings.TrimPrefix(p, docsImportPrefix))
+		}
+	}
+}
+
+// usedAsPackage reports whether every use of name in file qualifies a
+// selector, as with a package name
+func usedAsPackage(file *ast.File, name string) bool {
+	qualifiers := make(map[*ast.Ident]bool)
+	used := true
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if x, ok := node.X.(*ast.Ident); ok {
+				qualifiers[x] = true
+			}
+		case *ast.Ident:
+			if node.Name == name && !qualifiers[node] {
+				used = false
+			}
+		}
+		return used
+	})
+	return used
+}
+
+// addImport adds an import of path under name to file
+func addImport(file *ast.File, name, path string) {
+	spec := &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)}}
+	if path[strings.LastIndex(path, "/")+1:] != name {
+		spec.Name = ast.NewIdent(name)
+	}
+	file.Imports = append(file.Imports, spec)
+	file.Decls = append([]ast.Decl{&ast.GenDecl{Tok: token.IMPORT, Specs: []ast.Spec{spec}}}, file.Decls...)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// testCoreFiles is a minimal base-core module with a router package
+var testCoreFiles = map[string]string{
+	"go.mod": "module example.com/core\n\ngo 1.21\n",
+	"router/router.go": `package router
+
+type Router struct{}
+
+func New() *Router { return &Router{} }
+
+func (r *Router) GET(path string, handler func()) {}
+`,
+}
+
+const testExamplesDoc = "---\ntitle: Router\n---\n# Router\n" +
+	"## Valid\n```go\nimport \"base/core/router\"\n\nfunc setup() {\n\tr := router.New()\n\tr.GET(\"/posts\", func() {})\n}\n```\n" +
+	"## Broken\n```go\nimport \"base/core/router\"\n\nfunc setup() {\n\tr := router.New()\n\tr.GET(42, func() {})\n}\n```\n" +
+	"## Fragment\n```go\nr := router.New()\nr.GET(\"/\", func() {})\n```\n" +
+	"## Missing package\n```go\nimport \"base/core/storage\"\n\nvar s storage.Service\n```\n"
+
+func TestVerifyExamplesWithCore(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	core := t.TempDir()
+	for name, content := range testCoreFiles {
+		path := filepath.Join(core, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	goproxy := os.Getenv("GOPROXY")
+	workDir := t.TempDir()
+	prefix, err := prepareExampleModule(workDir, core)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefix != "example.com/core" {
+		t.Fatalf("prefix = %q, want example.com/core", prefix)
+	}
+
+	set := &docSet{Version: "v2", Locale: defaultLocale, fsys: fstest.MapFS{
+		"docs/router.md": {Data: []byte(testExamplesDoc)},
+	}}
+	checks := verifyExamples(set, workDir, prefix)
+
+	want := map[string]string{
+		"Router > Valid":           "ok",
+		"Router > Broken":          "broken",
+		"Router > Fragment":        "ok",
+		"Router > Missing package": "unverified",
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("checked %d examples, want %d: %+v", len(checks), len(want), checks)
+	}
+	for _, check := range checks {
+		if check.Status != want[check.Heading] {
+			t.Errorf("%s: status = %s %q, want %s", check.Heading, check.Status, check.Errors, want[check.Heading])
+		}
+	}
+
+	if got := os.Getenv("GOPROXY"); got != goproxy {
+		t.Errorf("GOPROXY = %q after verifying, want the process environment left alone (%q)", got, goproxy)
+	}
+}